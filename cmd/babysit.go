@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// babysitCheckInterval is how often the cached pods of a babysat deployment are checked.
+const babysitCheckInterval = 5 * time.Second
+
+var babysitCmd = &cobra.Command{
+	Use:   "babysit",
+	Short: "Keep a workload healthy during development by restarting stuck pods",
+}
+
+var babysitDeploymentCmd = &cobra.Command{
+	Use:   "deployment [name]",
+	Short: "Delete pods of a deployment that are stuck in CrashLoopBackOff",
+	Long: `Watch the pods of a deployment and delete any pod whose containers have been in
+CrashLoopBackOff for longer than --threshold, so its ReplicaSet creates a fresh one.
+Only pods controlled by one of the deployment's ReplicaSets are touched. A pod that
+replaces a deleted one inherits its restarts, so each replica is restarted at most
+--max-restarts-per-pod times; after that, it is reported but left alone. Runs until
+interrupted.`,
+	Aliases: []string{"deploy"},
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		threshold, _ := cmd.Flags().GetDuration("threshold")
+		maxRestarts, _ := cmd.Flags().GetInt("max-restarts-per-pod")
+		if threshold < 0 || maxRestarts < 0 {
+			exitWithError("Invalid flags", errors.New("--threshold and --max-restarts-per-pod must not be negative"))
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		clientset, err := getKubeClient()
+		if err != nil {
+			exitWithError("Failed to create Kubernetes client", err)
+		}
+		opts := babysitOptions{Threshold: threshold, MaxRestartsPerPod: maxRestarts}
+		if err := babysitDeployment(ctx, clientset, namespace, args[0], opts, os.Stdout); err != nil {
+			exitWithError("Failed to babysit deployment", err)
+		}
+	},
+}
+
+type babysitOptions struct {
+	Threshold         time.Duration
+	MaxRestartsPerPod int
+}
+
+// babysitDeployment runs a pod informer limited to the deployment's selector and deletes
+// crash-looping pods until ctx is done.
+func babysitDeployment(ctx context.Context, clientset kubernetes.Interface, ns, name string, opts babysitOptions, out io.Writer) error {
+	deployment, err := clientset.AppsV1().Deployments(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get deployment: %w", err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return fmt.Errorf("invalid deployment selector: %w", err)
+	}
+	log.Info().Str("name", name).Str("namespace", ns).Str("selector", selector.String()).
+		Dur("threshold", opts.Threshold).Int("max_restarts_per_pod", opts.MaxRestartsPerPod).Msg("Babysitting deployment")
+
+	factory := informers.NewSharedInformerFactoryWithOptions(clientset, 0,
+		informers.WithNamespace(ns),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = selector.String()
+		}),
+	)
+	// The deployment's ReplicaSets carry its template labels, so the selector matches them too.
+	pods := factory.Core().V1().Pods()
+	replicaSets := factory.Apps().V1().ReplicaSets()
+	podInformer, replicaSetInformer := pods.Informer(), replicaSets.Informer()
+	factory.Start(ctx.Done())
+	defer factory.Shutdown()
+	if !cache.WaitForCacheSync(ctx.Done(), podInformer.HasSynced, replicaSetInformer.HasSynced) {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return errors.New("pod informer failed to sync")
+	}
+
+	b := newBabysitter(clientset, deployment, replicaSets.Lister().ReplicaSets(ns), opts, out)
+	ticker := time.NewTicker(babysitCheckInterval)
+	defer ticker.Stop()
+	for {
+		cached, err := pods.Lister().Pods(ns).List(labels.Everything())
+		if err != nil {
+			return fmt.Errorf("failed to list cached pods: %w", err)
+		}
+		b.check(ctx, cached)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// crashLoop tracks when a pod was first and last seen in CrashLoopBackOff.
+type crashLoop struct {
+	since    time.Time
+	lastSeen time.Time
+}
+
+// babysitter decides which pods to delete. Containers run briefly between back-offs, so a
+// pod counts as stuck from the first CrashLoopBackOff it is seen in until it has stayed out
+// of CrashLoopBackOff for longer than the threshold.
+//
+// A deleted pod is replaced under a new name and UID, so restarts are counted per replica
+// rather than per pod object: the next new pod of the same ReplicaSet takes over the
+// restart count of the pod it replaces.
+type babysitter struct {
+	clientset   kubernetes.Interface
+	deployment  types.UID
+	replicaSets appslisters.ReplicaSetNamespaceLister
+	opts        babysitOptions
+	out         io.Writer
+	now         func() time.Time
+
+	loops map[types.UID]crashLoop
+	// restarts counts the deletions that led to each pod, inherited from the pods it replaces.
+	restarts map[types.UID]int
+	// replacing holds, per ReplicaSet, the restart counts of deleted pods whose
+	// replacement has not been seen yet.
+	replacing map[string][]int
+	exhausted map[types.UID]bool
+}
+
+// newBabysitter returns a babysitter for the pods of deployment.
+func newBabysitter(clientset kubernetes.Interface, deployment *appsv1.Deployment, replicaSets appslisters.ReplicaSetNamespaceLister, opts babysitOptions, out io.Writer) *babysitter {
+	return &babysitter{
+		clientset:   clientset,
+		deployment:  deployment.UID,
+		replicaSets: replicaSets,
+		opts:        opts,
+		out:         out,
+		now:         time.Now,
+		loops:       map[types.UID]crashLoop{},
+		restarts:    map[types.UID]int{},
+		replacing:   map[string][]int{},
+		exhausted:   map[types.UID]bool{},
+	}
+}
+
+// owningReplicaSet returns the name of the pod's controlling ReplicaSet if that ReplicaSet
+// is controlled by the babysat deployment. Pods that merely match the selector, such as
+// bare pods or pods of another deployment with overlapping labels, are left alone.
+func (b *babysitter) owningReplicaSet(pod *corev1.Pod) (string, bool) {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil || ref.Kind != "ReplicaSet" {
+		return "", false
+	}
+	rs, err := b.replicaSets.Get(ref.Name)
+	if err != nil || rs.UID != ref.UID {
+		return "", false
+	}
+	owner := metav1.GetControllerOf(rs)
+	if owner == nil || owner.Kind != "Deployment" || owner.UID != b.deployment {
+		return "", false
+	}
+	return rs.Name, true
+}
+
+// check deletes the pods that have been stuck for longer than the threshold, as long as
+// their replica has not used up --max-restarts-per-pod.
+func (b *babysitter) check(ctx context.Context, pods []*corev1.Pod) {
+	now := b.now()
+	present := make(map[types.UID]bool, len(pods))
+	for _, pod := range pods {
+		present[pod.UID] = true
+		owner, owned := b.owningReplicaSet(pod)
+		if !owned || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if _, seen := b.restarts[pod.UID]; !seen {
+			b.restarts[pod.UID] = 0
+			if pending := b.replacing[owner]; len(pending) > 0 {
+				b.restarts[pod.UID], b.replacing[owner] = pending[0], pending[1:]
+			}
+		}
+		loop, tracked := b.loops[pod.UID]
+		status, crashing := crashLoopingContainer(pod)
+		if !crashing {
+			if tracked && now.Sub(loop.lastSeen) > b.opts.Threshold {
+				delete(b.loops, pod.UID)
+			}
+			continue
+		}
+		if !tracked {
+			loop.since = now
+		}
+		loop.lastSeen = now
+		b.loops[pod.UID] = loop
+		if stuck := now.Sub(loop.since); stuck >= b.opts.Threshold {
+			b.restart(ctx, pod, owner, status, stuck)
+		}
+	}
+	for uid := range b.loops {
+		if !present[uid] {
+			delete(b.loops, uid)
+		}
+	}
+	for uid := range b.restarts {
+		if !present[uid] {
+			delete(b.restarts, uid)
+			delete(b.exhausted, uid)
+		}
+	}
+}
+
+func (b *babysitter) restart(ctx context.Context, pod *corev1.Pod, owner string, status corev1.ContainerStatus, stuck time.Duration) {
+	restarts := b.restarts[pod.UID]
+	if restarts >= b.opts.MaxRestartsPerPod {
+		if !b.exhausted[pod.UID] {
+			b.exhausted[pod.UID] = true
+			b.printf("SKIPPED  pod %s/%s: container %s in CrashLoopBackOff for %s, but its replica of %s was already restarted %d of %d times",
+				pod.Namespace, pod.Name, status.Name, stuck.Round(time.Second), owner, restarts, b.opts.MaxRestartsPerPod)
+		}
+		return
+	}
+
+	// The UID precondition keeps a replacement pod with the same name from being deleted.
+	err := b.clientset.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &pod.UID}})
+	if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		log.Warn().Err(err).Str("pod", pod.Name).Str("namespace", pod.Namespace).Msg("Failed to delete crash-looping pod")
+		return
+	}
+	delete(b.loops, pod.UID)
+	if err != nil {
+		return
+	}
+	b.replacing[owner] = append(b.replacing[owner], restarts+1)
+	b.printf("DELETED  pod %s/%s: container %s in CrashLoopBackOff for %s with %d container restarts (restart %d of %d for its replica of %s)",
+		pod.Namespace, pod.Name, status.Name, stuck.Round(time.Second), status.RestartCount, restarts+1, b.opts.MaxRestartsPerPod, owner)
+}
+
+func (b *babysitter) printf(format string, args ...any) {
+	fmt.Fprintf(b.out, "%s  "+format+"\n", append([]any{b.now().Format("15:04:05")}, args...)...)
+}
+
+// crashLoopingContainer returns the first container, init containers included, that is
+// waiting in CrashLoopBackOff after at least one restart.
+func crashLoopingContainer(pod *corev1.Pod) (corev1.ContainerStatus, bool) {
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, status := range statuses {
+			if waiting := status.State.Waiting; waiting != nil && waiting.Reason == "CrashLoopBackOff" && status.RestartCount > 0 {
+				return status, true
+			}
+		}
+	}
+	return corev1.ContainerStatus{}, false
+}
+
+func init() {
+	rootCmd.AddCommand(babysitCmd)
+	babysitCmd.AddCommand(babysitDeploymentCmd)
+	babysitDeploymentCmd.Flags().Duration("threshold", time.Minute, "How long a pod may stay in CrashLoopBackOff before it is deleted")
+	babysitDeploymentCmd.Flags().Int("max-restarts-per-pod", 3, "Times each replica may be restarted; a replacement pod inherits the restarts of the pod it replaces, so a pod that never recovers is not deleted in a loop")
+}
@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func babysatDeployment(replicas int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "web"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		},
+	}
+}
+
+// controllerRef returns a controller owner reference whose UID is the owner's name.
+func controllerRef(kind, name string) []metav1.OwnerReference {
+	controller := true
+	return []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: kind, Name: name, UID: types.UID(name), Controller: &controller}}
+}
+
+func ownedReplicaSet(name, deployment string) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{
+		Name:            name,
+		Namespace:       "default",
+		UID:             types.UID(name),
+		Labels:          map[string]string{"app": "web"},
+		OwnerReferences: controllerRef("Deployment", deployment),
+	}}
+}
+
+func replicaSetLister(t *testing.T, replicaSets ...*appsv1.ReplicaSet) appslisters.ReplicaSetNamespaceLister {
+	t.Helper()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, rs := range replicaSets {
+		if err := indexer.Add(rs); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return appslisters.NewReplicaSetLister(indexer).ReplicaSets("default")
+}
+
+// crashLoopingPod returns a crash-looping pod controlled by the ReplicaSet web-7d4b8c.
+func crashLoopingPod(name string, restarts int32) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			UID:             types.UID(name),
+			OwnerReferences: controllerRef("ReplicaSet", "web-7d4b8c"),
+		},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name:         "app",
+			RestartCount: restarts,
+			State:        corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		}}},
+	}
+}
+
+func TestCrashLoopingContainer(t *testing.T) {
+	if status, ok := crashLoopingContainer(crashLoopingPod("web-1", 4)); !ok || status.Name != "app" {
+		t.Errorf("expected container app to be crash-looping, got %v %v", status.Name, ok)
+	}
+
+	running := crashLoopingPod("web-1", 4)
+	running.Status.ContainerStatuses[0].State = corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	if _, ok := crashLoopingContainer(running); ok {
+		t.Error("running container reported as crash-looping")
+	}
+
+	pulling := crashLoopingPod("web-1", 0)
+	pulling.Status.ContainerStatuses[0].State.Waiting.Reason = "ImagePullBackOff"
+	if _, ok := crashLoopingContainer(pulling); ok {
+		t.Error("ImagePullBackOff reported as crash-looping")
+	}
+
+	initCrash := &corev1.Pod{Status: corev1.PodStatus{InitContainerStatuses: crashLoopingPod("web-1", 2).Status.ContainerStatuses}}
+	if _, ok := crashLoopingContainer(initCrash); !ok {
+		t.Error("crash-looping init container not detected")
+	}
+}
+
+func TestBabysitter_Check(t *testing.T) {
+	ctx := context.Background()
+	stuck, recovering := crashLoopingPod("web-1", 5), crashLoopingPod("web-2", 1)
+	clientset := fake.NewClientset(stuck, recovering)
+
+	var out bytes.Buffer
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	opts := babysitOptions{Threshold: time.Minute, MaxRestartsPerPod: 1}
+	b := newBabysitter(clientset, babysatDeployment(3), replicaSetLister(t, ownedReplicaSet("web-7d4b8c", "web")), opts, &out)
+	b.now = func() time.Time { return now }
+
+	b.check(ctx, []*corev1.Pod{stuck, recovering})
+	if out.Len() != 0 {
+		t.Fatalf("pods were deleted before reaching the threshold:\n%s", out.String())
+	}
+
+	// web-2 comes up in between; a brief run does not reset the clock of web-1.
+	now = now.Add(30 * time.Second)
+	healthy := recovering.DeepCopy()
+	healthy.Status.ContainerStatuses[0].State = corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	b.check(ctx, []*corev1.Pod{stuck, healthy})
+	now = now.Add(40 * time.Second)
+	b.check(ctx, []*corev1.Pod{stuck, healthy})
+
+	if got := out.String(); !strings.Contains(got, "DELETED  pod default/web-1: container app in CrashLoopBackOff for 1m10s with 5 container restarts (restart 1 of 1 for its replica of web-7d4b8c)") {
+		t.Errorf("unexpected output:\n%s", got)
+	}
+	if _, err := clientset.CoreV1().Pods("default").Get(ctx, "web-1", metav1.GetOptions{}); err == nil {
+		t.Error("stuck pod web-1 was not deleted")
+	}
+	if _, err := clientset.CoreV1().Pods("default").Get(ctx, "web-2", metav1.GetOptions{}); err != nil {
+		t.Errorf("recovered pod web-2 was deleted: %v", err)
+	}
+
+	// The replacement also crash-loops, but it inherits the restart of web-1, which used up
+	// the cap for that replica however many replicas the deployment has.
+	out.Reset()
+	replacement := crashLoopingPod("web-3", 2)
+	if _, err := clientset.CoreV1().Pods("default").Create(ctx, replacement, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		b.check(ctx, []*corev1.Pod{replacement, healthy})
+		now = now.Add(time.Minute)
+	}
+	if got := out.String(); strings.Count(got, "SKIPPED") != 1 || !strings.Contains(got, "its replica of web-7d4b8c was already restarted 1 of 1 times") {
+		t.Errorf("expected a single skip once the replica's restarts are used up, got:\n%s", got)
+	}
+	if _, err := clientset.CoreV1().Pods("default").Get(ctx, "web-3", metav1.GetOptions{}); err != nil {
+		t.Errorf("pod web-3 was deleted beyond the cap: %v", err)
+	}
+
+	// Another replica has its own restarts left.
+	out.Reset()
+	for range 2 {
+		b.check(ctx, []*corev1.Pod{replacement, recovering})
+		now = now.Add(time.Minute)
+	}
+	if got := out.String(); !strings.Contains(got, "DELETED  pod default/web-2") {
+		t.Errorf("expected web-2 to be restarted, got:\n%s", got)
+	}
+}
+
+func TestBabysitter_OnlyDeploymentPods(t *testing.T) {
+	ctx := context.Background()
+	bare := crashLoopingPod("bare-1", 5)
+	bare.OwnerReferences = nil
+	otherDeployment := crashLoopingPod("api-1", 5)
+	otherDeployment.OwnerReferences = controllerRef("ReplicaSet", "api-5f6c9d")
+	statefulSet := crashLoopingPod("db-0", 5)
+	statefulSet.OwnerReferences = controllerRef("StatefulSet", "db")
+	unknownReplicaSet := crashLoopingPod("old-1", 5)
+	unknownReplicaSet.OwnerReferences = controllerRef("ReplicaSet", "web-gone")
+	pods := []*corev1.Pod{crashLoopingPod("web-1", 5), bare, otherDeployment, statefulSet, unknownReplicaSet}
+
+	clientset := fake.NewClientset()
+	for _, pod := range pods {
+		if _, err := clientset.CoreV1().Pods("default").Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var out bytes.Buffer
+	replicaSets := replicaSetLister(t, ownedReplicaSet("web-7d4b8c", "web"), ownedReplicaSet("api-5f6c9d", "api"))
+	b := newBabysitter(clientset, babysatDeployment(1), replicaSets, babysitOptions{MaxRestartsPerPod: 5}, &out)
+	b.check(ctx, pods)
+
+	if got := out.String(); strings.Count(got, "DELETED") != 1 || !strings.Contains(got, "pod default/web-1") {
+		t.Errorf("expected only web-1 to be deleted, got:\n%s", got)
+	}
+	for _, name := range []string{"bare-1", "api-1", "db-0", "old-1"} {
+		if _, err := clientset.CoreV1().Pods("default").Get(ctx, name, metav1.GetOptions{}); err != nil {
+			t.Errorf("pod %s not controlled by the deployment was deleted: %v", name, err)
+		}
+	}
+}
+
+func TestBabysitDeployment(t *testing.T) {
+	otherDeployment := withLabels(crashLoopingPod("api-1", 3), "web")
+	otherDeployment.OwnerReferences = controllerRef("ReplicaSet", "api-5f6c9d")
+	clientset := fake.NewClientset(
+		babysatDeployment(2),
+		ownedReplicaSet("web-7d4b8c", "web"),
+		ownedReplicaSet("api-5f6c9d", "api"),
+		withLabels(crashLoopingPod("web-1", 3), "web"),
+		withLabels(crashLoopingPod("other-1", 3), "other"),
+		otherDeployment,
+	)
+	var out lockedBuffer
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	// A zero threshold deletes crash-looping pods on the first check.
+	go func() {
+		done <- babysitDeployment(ctx, clientset, "default", "web", babysitOptions{MaxRestartsPerPod: 1}, &out)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), "DELETED") {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for the crash-looping pod to be deleted, output:\n%s", out.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("babysitDeployment returned error: %v", err)
+	}
+
+	got := out.String()
+	if !strings.Contains(got, "pod default/web-1") || !strings.Contains(got, "(restart 1 of 1 for its replica of web-7d4b8c)") {
+		t.Errorf("unexpected output:\n%s", got)
+	}
+	if strings.Contains(got, "other-1") || strings.Contains(got, "api-1") {
+		t.Errorf("pod outside the deployment was touched:\n%s", got)
+	}
+}
+
+func TestBabysitDeployment_NotFound(t *testing.T) {
+	var out lockedBuffer
+	err := babysitDeployment(context.Background(), fake.NewClientset(), "default", "missing", babysitOptions{}, &out)
+	if err == nil || !strings.Contains(err.Error(), "failed to get deployment") {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func withLabels(pod *corev1.Pod, app string) *corev1.Pod {
+	pod.Labels = map[string]string{"app": app}
+	return pod
+}
+
+func TestBabysitDeploymentFlags(t *testing.T) {
+	if babysitDeploymentCmd.Flags().Lookup("max-restarts-per-pod") == nil {
+		t.Error("expected the max-restarts-per-pod flag to be defined")
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cast"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// configEnvPrefix is prepended to flag names to form environment variable
+// names, e.g. --enable-informer becomes K8S_CONTROLLER_ENABLE_INFORMER.
+const configEnvPrefix = "K8S_CONTROLLER"
+
+// bindConfig fills the command's flags from an optional YAML config file and
+// from environment variables. Values are applied with the following
+// precedence, lowest to highest: config file, command line flags, environment.
+// Keys in the config file use the flag names, e.g. "port" or "log-level".
+func bindConfig(cmd *cobra.Command, configFile string) error {
+	v := viper.New()
+	if configFile != "" {
+		v.SetConfigFile(configFile)
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("failed to read config file %s: %w", configFile, err)
+		}
+	}
+	v.SetEnvPrefix(configEnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+	v.AutomaticEnv()
+
+	var errs []error
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Name == "config" || f.Name == "help" {
+			return
+		}
+		_, fromEnv := os.LookupEnv(configEnvKey(f.Name))
+		if !fromEnv && (f.Changed || !v.IsSet(f.Name)) {
+			return
+		}
+		if err := setFlagValue(cmd.Flags(), f, v.Get(f.Name)); err != nil {
+			errs = append(errs, fmt.Errorf("invalid value for %s: %w", f.Name, err))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+func configEnvKey(flagName string) string {
+	return configEnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+func setFlagValue(flags *pflag.FlagSet, f *pflag.Flag, value any) error {
+	if list, ok := value.([]any); ok {
+		sv, ok := f.Value.(pflag.SliceValue)
+		if !ok {
+			return fmt.Errorf("flag does not accept a list")
+		}
+		return sv.Replace(cast.ToStringSlice(list))
+	}
+	s, err := cast.ToStringE(value)
+	if err != nil {
+		return err
+	}
+	return flags.Set(f.Name, s)
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newConfigTestCommand() *cobra.Command {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().Int("port", 8080, "")
+	cmd.Flags().String("log-level", "info", "")
+	cmd.Flags().String("namespace", "default", "")
+	cmd.Flags().Bool("enable-informer", true, "")
+	cmd.Flags().StringSlice("watch", nil, "")
+	return cmd
+}
+
+func TestBindConfig_Precedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "port: 9090\nlog-level: debug\nnamespace: from-file\nenable-informer: false\nwatch: [deployments, pods]\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("K8S_CONTROLLER_NAMESPACE", "from-env")
+
+	cmd := newConfigTestCommand()
+	if err := cmd.ParseFlags([]string{"--log-level", "warn", "--namespace", "from-flag"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bindConfig(cmd, path); err != nil {
+		t.Fatalf("bindConfig returned error: %v", err)
+	}
+
+	if got, _ := cmd.Flags().GetInt("port"); got != 9090 {
+		t.Errorf("port = %d, want 9090 from config file", got)
+	}
+	if got, _ := cmd.Flags().GetString("log-level"); got != "warn" {
+		t.Errorf("log-level = %q, want flag value 'warn'", got)
+	}
+	if got, _ := cmd.Flags().GetString("namespace"); got != "from-env" {
+		t.Errorf("namespace = %q, want env value 'from-env'", got)
+	}
+	if got, _ := cmd.Flags().GetBool("enable-informer"); got {
+		t.Error("enable-informer = true, want false from config file")
+	}
+	if got, _ := cmd.Flags().GetStringSlice("watch"); len(got) != 2 || got[0] != "deployments" || got[1] != "pods" {
+		t.Errorf("watch = %v, want [deployments pods]", got)
+	}
+}
+
+func TestBindConfig_NoFile(t *testing.T) {
+	cmd := newConfigTestCommand()
+	if err := cmd.ParseFlags(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := bindConfig(cmd, ""); err != nil {
+		t.Fatalf("bindConfig returned error: %v", err)
+	}
+	if got, _ := cmd.Flags().GetInt("port"); got != 8080 {
+		t.Errorf("port = %d, want default 8080", got)
+	}
+}
+
+func TestBindConfig_MissingFile(t *testing.T) {
+	cmd := newConfigTestCommand()
+	if err := bindConfig(cmd, "/invalid/path/config.yaml"); err == nil {
+		t.Error("expected error for missing config file")
+	}
+}
+
+func TestBindConfig_InvalidValue(t *testing.T) {
+	t.Setenv("K8S_CONTROLLER_PORT", "not-a-number")
+	cmd := newConfigTestCommand()
+	if err := bindConfig(cmd, ""); err == nil {
+		t.Error("expected error for invalid port value")
+	}
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// containerOptions adds containers beyond the positional one, such as sidecars, and
+// init containers to created pods.
+type containerOptions struct {
+	Containers     []corev1.Container
+	InitContainers []corev1.Container
+}
+
+// apply appends the containers to spec and sets its init containers.
+func (o containerOptions) apply(spec *corev1.PodSpec) {
+	spec.Containers = append(spec.Containers, o.Containers...)
+	spec.InitContainers = o.InitContainers
+}
+
+// addContainerFlags registers --container and --init-container on a create command.
+func addContainerFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("container", nil, "Add a container, as name=image (repeatable); makes the positional [name] [image] optional")
+	cmd.Flags().StringArray("init-container", nil, "Add an init container, run to completion before the containers start, as name=image (repeatable)")
+}
+
+// containersFromFlags parses the flags registered by addContainerFlags.
+func containersFromFlags(cmd *cobra.Command) (containerOptions, error) {
+	containers, _ := cmd.Flags().GetStringArray("container")
+	initContainers, _ := cmd.Flags().GetStringArray("init-container")
+	return parseContainerOptions(containers, initContainers)
+}
+
+func parseContainerOptions(containers, initContainers []string) (containerOptions, error) {
+	var opts containerOptions
+	for _, s := range containers {
+		c, err := parseContainer("--container", s)
+		if err != nil {
+			return opts, err
+		}
+		opts.Containers = append(opts.Containers, c)
+	}
+	for _, s := range initContainers {
+		c, err := parseContainer("--init-container", s)
+		if err != nil {
+			return opts, err
+		}
+		opts.InitContainers = append(opts.InitContainers, c)
+	}
+	return opts, nil
+}
+
+func parseContainer(flag, s string) (corev1.Container, error) {
+	name, image, ok := strings.Cut(s, "=")
+	if !ok || name == "" || image == "" {
+		return corev1.Container{}, fmt.Errorf("invalid %s %q: must be name=image", flag, s)
+	}
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return corev1.Container{}, fmt.Errorf("invalid %s name %q: %s", flag, name, strings.Join(errs, "; "))
+	}
+	return corev1.Container{Name: name, Image: image}, nil
+}
+
+// resolveCreateArgs returns the object name and the image of the positional container
+// for create deployment and create pod. With --container flags the image, and even the
+// name, may be omitted; the name then defaults to the first --container. An empty image
+// means there is no positional container. Container names must be unique across
+// containers and init containers.
+func resolveCreateArgs(args []string, opts containerOptions) (string, string, error) {
+	var name, image string
+	switch {
+	case len(args) == 2:
+		name, image = args[0], args[1]
+	case len(opts.Containers) == 0:
+		return "", "", errors.New("requires [name] [image], or at least one --container name=image")
+	case len(args) == 1:
+		name = args[0]
+	default:
+		name = opts.Containers[0].Name
+	}
+
+	seen := map[string]bool{}
+	if image != "" {
+		seen[name] = true
+	}
+	for _, c := range append(append([]corev1.Container{}, opts.Containers...), opts.InitContainers...) {
+		if seen[c.Name] {
+			return "", "", fmt.Errorf("duplicate container name %q", c.Name)
+		}
+		seen[c.Name] = true
+	}
+	return name, image, nil
+}
+
+// primaryContainer is the container given by the positional [name] [image], or none
+// when the image was omitted.
+func primaryContainer(name, image string) []corev1.Container {
+	if image == "" {
+		return nil
+	}
+	return []corev1.Container{
+		{
+			Name:  name,
+			Image: image,
+			Ports: []corev1.ContainerPort{
+				{
+					ContainerPort: 80,
+					Protocol:      corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseContainerOptions(t *testing.T) {
+	opts, err := parseContainerOptions([]string{"app=nginx:1.27", "proxy=envoyproxy/envoy:v1.31"}, []string{"migrate=flyway:10"})
+	if err != nil {
+		t.Fatalf("parseContainerOptions returned error: %v", err)
+	}
+	if len(opts.Containers) != 2 || opts.Containers[1].Name != "proxy" || opts.Containers[1].Image != "envoyproxy/envoy:v1.31" {
+		t.Errorf("containers = %+v", opts.Containers)
+	}
+	if len(opts.InitContainers) != 1 || opts.InitContainers[0].Name != "migrate" {
+		t.Errorf("init containers = %+v", opts.InitContainers)
+	}
+
+	for _, tt := range []struct{ containers, initContainers []string }{
+		{containers: []string{"nginx"}},
+		{containers: []string{"=nginx"}},
+		{containers: []string{"app="}},
+		{containers: []string{"App_1=nginx"}},
+		{initContainers: []string{"migrate"}},
+	} {
+		if _, err := parseContainerOptions(tt.containers, tt.initContainers); err == nil {
+			t.Errorf("expected error for --container %v --init-container %v", tt.containers, tt.initContainers)
+		}
+	}
+}
+
+func TestResolveCreateArgs(t *testing.T) {
+	sidecars, err := parseContainerOptions([]string{"app=nginx", "proxy=envoy"}, []string{"init=busybox"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		args      []string
+		opts      containerOptions
+		wantName  string
+		wantImage string
+		wantErr   string
+	}{
+		{name: "positional only", args: []string{"web", "nginx"}, wantName: "web", wantImage: "nginx"},
+		{name: "positional plus sidecars", args: []string{"web", "nginx"}, opts: sidecars, wantName: "web", wantImage: "nginx"},
+		{name: "name only", args: []string{"web"}, opts: sidecars, wantName: "web"},
+		{name: "no arguments", opts: sidecars, wantName: "app"},
+		{name: "missing image", args: []string{"web"}, wantErr: "requires [name] [image]"},
+		{name: "nothing", wantErr: "requires [name] [image]"},
+		{name: "positional clashes with sidecar", args: []string{"app", "nginx"}, opts: sidecars, wantErr: `duplicate container name "app"`},
+		{name: "init clashes with container", args: []string{"web"}, opts: containerOptions{
+			Containers:     sidecars.Containers,
+			InitContainers: sidecars.Containers[:1],
+		}, wantErr: `duplicate container name "app"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, image, err := resolveCreateArgs(tt.args, tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if name != tt.wantName || image != tt.wantImage {
+				t.Errorf("got name %q image %q, want %q %q", name, image, tt.wantName, tt.wantImage)
+			}
+		})
+	}
+}
+
+func TestBuildWithContainers(t *testing.T) {
+	opts, err := parseContainerOptions([]string{"proxy=envoy"}, []string{"migrate=flyway"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dep := buildDeployment("web", "nginx", deploymentOptions{Replicas: 1, Containers: opts})
+	spec := dep.Spec.Template.Spec
+	if len(spec.Containers) != 2 || spec.Containers[0].Name != "web" || spec.Containers[1].Name != "proxy" {
+		t.Errorf("deployment containers = %+v, want web then proxy", spec.Containers)
+	}
+	if len(spec.InitContainers) != 1 || spec.InitContainers[0].Image != "flyway" {
+		t.Errorf("deployment init containers = %+v", spec.InitContainers)
+	}
+
+	// Without a positional image only the --container containers are added.
+	pod := buildPod("web", "", schedulingOptions{}, opts)
+	if len(pod.Spec.Containers) != 1 || pod.Spec.Containers[0].Name != "proxy" {
+		t.Errorf("pod containers = %+v, want only proxy", pod.Spec.Containers)
+	}
+	if len(pod.Spec.InitContainers) != 1 {
+		t.Errorf("pod init containers = %+v", pod.Spec.InitContainers)
+	}
+}
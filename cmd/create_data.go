@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+var createSecretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "Create a Kubernetes secret",
+}
+
+var createSecretGenericCmd = &cobra.Command{
+	Use:   "generic [name]",
+	Short: "Create a secret from literal values or files",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := dataFromFlags(cmd)
+		if err != nil {
+			exitWithError("Invalid secret data", err)
+		}
+		metadata, err := metadataFromFlags(cmd)
+		if err != nil {
+			exitWithError("Invalid metadata", err)
+		}
+		secretType, _ := cmd.Flags().GetString("type")
+		if err := createSecret(args[0], corev1.SecretType(secretType), data, metadata); err != nil {
+			exitWithError("Failed to create secret", err)
+		}
+	},
+}
+
+var createConfigMapCmd = &cobra.Command{
+	Use:     "configmap [name]",
+	Short:   "Create a configmap from literal values or files",
+	Aliases: []string{"cm"},
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := dataFromFlags(cmd)
+		if err != nil {
+			exitWithError("Invalid configmap data", err)
+		}
+		metadata, err := metadataFromFlags(cmd)
+		if err != nil {
+			exitWithError("Invalid metadata", err)
+		}
+		if err := createConfigMap(args[0], data, metadata); err != nil {
+			exitWithError("Failed to create configmap", err)
+		}
+	},
+}
+
+func dataFromFlags(cmd *cobra.Command) (map[string][]byte, error) {
+	literals, _ := cmd.Flags().GetStringArray("from-literal")
+	files, _ := cmd.Flags().GetStringArray("from-file")
+	return parseDataSources(literals, files)
+}
+
+// parseDataSources collects key/value pairs from --from-literal=key=value and
+// --from-file=[key=]path flags. File keys default to the file's base name.
+func parseDataSources(literals, files []string) (map[string][]byte, error) {
+	data := map[string][]byte{}
+	add := func(key string, value []byte) error {
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			return fmt.Errorf("invalid key %q: %s", key, strings.Join(errs, "; "))
+		}
+		if _, ok := data[key]; ok {
+			return fmt.Errorf("duplicate key %q", key)
+		}
+		data[key] = value
+		return nil
+	}
+
+	for _, literal := range literals {
+		key, value, ok := strings.Cut(literal, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --from-literal %q: expected key=value", literal)
+		}
+		if err := add(key, []byte(value)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, source := range files {
+		key, path, ok := strings.Cut(source, "=")
+		if !ok {
+			key, path = filepath.Base(source), source
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --from-file %q: %w", source, err)
+		}
+		if !info.Mode().IsRegular() {
+			return nil, fmt.Errorf("invalid --from-file %q: not a regular file", source)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if err := add(key, content); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// buildSecret stores values in Data, which the API encodes as base64 on the wire.
+func buildSecret(name string, secretType corev1.SecretType, data map[string][]byte) *corev1.Secret {
+	if secretType == "" {
+		secretType = corev1.SecretTypeOpaque
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Type:       secretType,
+		Data:       data,
+	}
+}
+
+// buildConfigMap keeps UTF-8 values in Data and moves binary values to BinaryData.
+func buildConfigMap(name string, data map[string][]byte) *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	for key, value := range data {
+		if utf8.Valid(value) {
+			if cm.Data == nil {
+				cm.Data = map[string]string{}
+			}
+			cm.Data[key] = string(value)
+			continue
+		}
+		if cm.BinaryData == nil {
+			cm.BinaryData = map[string][]byte{}
+		}
+		cm.BinaryData[key] = value
+	}
+	return cm
+}
+
+func createSecret(name string, secretType corev1.SecretType, data map[string][]byte, metadata metadataOptions) error {
+	log.Info().Str("name", name).Str("type", string(secretType)).Int("keys", len(data)).Str("namespace", namespace).Msg("Creating secret")
+
+	clientset, err := getKubeClient()
+	if err != nil {
+		return err
+	}
+	if err := ensureNamespace(context.Background(), clientset, namespace, createMissingNamespace); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	secrets := clientset.CoreV1().Secrets(namespace)
+	created, err := createOnce(os.Stdout, fmt.Sprintf("Secret '%s' in namespace '%s'", name, namespace), func() error {
+		_, err := secrets.Get(ctx, name, metav1.GetOptions{})
+		return err
+	}, func() error {
+		secret := buildSecret(name, secretType, data)
+		metadata.apply(&secret.ObjectMeta)
+		_, err := secrets.Create(ctx, secret, metav1.CreateOptions{FieldManager: fieldManager})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create secret: %w", err)
+	}
+
+	if created {
+		fmt.Printf("Secret '%s' created successfully in namespace '%s'\n", name, namespace)
+	}
+	return nil
+}
+
+func createConfigMap(name string, data map[string][]byte, metadata metadataOptions) error {
+	log.Info().Str("name", name).Int("keys", len(data)).Str("namespace", namespace).Msg("Creating configmap")
+
+	clientset, err := getKubeClient()
+	if err != nil {
+		return err
+	}
+	if err := ensureNamespace(context.Background(), clientset, namespace, createMissingNamespace); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	configMaps := clientset.CoreV1().ConfigMaps(namespace)
+	created, err := createOnce(os.Stdout, fmt.Sprintf("ConfigMap '%s' in namespace '%s'", name, namespace), func() error {
+		_, err := configMaps.Get(ctx, name, metav1.GetOptions{})
+		return err
+	}, func() error {
+		cm := buildConfigMap(name, data)
+		metadata.apply(&cm.ObjectMeta)
+		_, err := configMaps.Create(ctx, cm, metav1.CreateOptions{FieldManager: fieldManager})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create configmap: %w", err)
+	}
+
+	if created {
+		fmt.Printf("ConfigMap '%s' created successfully in namespace '%s'\n", name, namespace)
+	}
+	return nil
+}
+
+func init() {
+	createCmd.AddCommand(createSecretCmd)
+	createCmd.AddCommand(createConfigMapCmd)
+	createSecretCmd.AddCommand(createSecretGenericCmd)
+
+	for _, cmd := range []*cobra.Command{createSecretGenericCmd, createConfigMapCmd} {
+		cmd.Flags().StringArray("from-literal", nil, "Key and literal value to add, as key=value (repeatable)")
+		cmd.Flags().StringArray("from-file", nil, "File to add, as path or key=path; the key defaults to the file name (repeatable)")
+	}
+	createSecretGenericCmd.Flags().String("type", string(corev1.SecretTypeOpaque), "Secret type, e.g. Opaque or kubernetes.io/basic-auth")
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParseDataSources(t *testing.T) {
+	dir := t.TempDir()
+	certPath := filepath.Join(dir, "tls.crt")
+	if err := os.WriteFile(certPath, []byte("CERT"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := parseDataSources([]string{"user=admin", "dsn=postgres://db?sslmode=disable"}, []string{certPath, "ca=" + certPath})
+	if err != nil {
+		t.Fatalf("parseDataSources returned error: %v", err)
+	}
+	want := map[string]string{"user": "admin", "dsn": "postgres://db?sslmode=disable", "tls.crt": "CERT", "ca": "CERT"}
+	if len(data) != len(want) {
+		t.Fatalf("got %d keys, want %d: %v", len(data), len(want), data)
+	}
+	for key, value := range want {
+		if string(data[key]) != value {
+			t.Errorf("data[%q] = %q, want %q", key, data[key], value)
+		}
+	}
+}
+
+func TestParseDataSources_Errors(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name     string
+		literals []string
+		files    []string
+	}{
+		{"missing value separator", []string{"novalue"}, nil},
+		{"empty key", []string{"=value"}, nil},
+		{"invalid key", []string{"bad key=value"}, nil},
+		{"duplicate key", []string{"a=1", "a=2"}, nil},
+		{"missing file", nil, []string{filepath.Join(dir, "missing.txt")}},
+		{"directory", nil, []string{dir}},
+	}
+	for _, tt := range tests {
+		if _, err := parseDataSources(tt.literals, tt.files); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestBuildSecretAndConfigMap(t *testing.T) {
+	data := map[string][]byte{"text": []byte("hello"), "blob": {0xff, 0xfe}}
+
+	secret := buildSecret("creds", "", data)
+	if secret.Type != corev1.SecretTypeOpaque {
+		t.Errorf("secret type = %q, want %q", secret.Type, corev1.SecretTypeOpaque)
+	}
+	if string(secret.Data["text"]) != "hello" {
+		t.Errorf("secret data not set: %v", secret.Data)
+	}
+
+	cm := buildConfigMap("settings", data)
+	if cm.Data["text"] != "hello" {
+		t.Errorf("configmap Data[text] = %q, want %q", cm.Data["text"], "hello")
+	}
+	if _, ok := cm.BinaryData["blob"]; !ok {
+		t.Errorf("expected non-UTF-8 value in BinaryData, got %v", cm.BinaryData)
+	}
+}
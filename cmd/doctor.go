@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var doctorTimeout time.Duration
+
+var doctorCmd = &cobra.Command{
+	Use:     "doctor",
+	Short:   "Check connectivity and permissions against the Kubernetes API",
+	Long:    "Resolve the kubeconfig, ping the API server, list namespaces and check RBAC permissions, printing a pass/fail checklist",
+	Aliases: []string{"healthcheck"},
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+		defer cancel()
+		if !runDoctor(ctx, os.Stdout) {
+			os.Exit(1)
+		}
+	},
+}
+
+// doctorCheck is a single step of the doctor report. Critical checks make the command exit non-zero.
+type doctorCheck struct {
+	Name     string
+	Critical bool
+	Run      func(ctx context.Context) (string, error)
+}
+
+type doctorResult struct {
+	Name     string
+	Critical bool
+	Passed   bool
+	Detail   string
+	Latency  time.Duration
+}
+
+// runDoctor runs all checks, prints the report and reports whether every critical check passed.
+func runDoctor(ctx context.Context, out io.Writer) bool {
+	var clientset kubernetes.Interface
+	checks := []doctorCheck{{
+		Name:     "Resolve kubeconfig",
+		Critical: true,
+		Run: func(ctx context.Context) (string, error) {
+			config, err := getKubeConfig()
+			if err != nil {
+				return "", err
+			}
+			// Not every client call takes a context, e.g. ServerVersion, so the HTTP client
+			// itself gives up when --timeout runs out.
+			if deadline, ok := ctx.Deadline(); ok {
+				config.Timeout = time.Until(deadline)
+			}
+			clientset, err = kubernetes.NewForConfig(config)
+			if err != nil {
+				return "", fmt.Errorf("failed to create client: %w", err)
+			}
+			return config.Host, nil
+		},
+	}}
+	results := runDoctorChecks(ctx, checks)
+	if results[0].Passed {
+		results = append(results, runDoctorChecks(ctx, apiDoctorChecks(clientset, namespace))...)
+	}
+
+	printDoctorResults(out, results)
+	return !hasCriticalFailure(results)
+}
+
+// apiDoctorChecks returns the checks that exercise the API server through the given client.
+func apiDoctorChecks(clientset kubernetes.Interface, ns string) []doctorCheck {
+	checks := []doctorCheck{
+		{
+			Name:     "API server version",
+			Critical: true,
+			Run: func(ctx context.Context) (string, error) {
+				info, err := clientset.Discovery().ServerVersion()
+				if err != nil {
+					return "", err
+				}
+				return info.GitVersion, nil
+			},
+		},
+		{
+			Name:     "List namespaces",
+			Critical: true,
+			Run: func(ctx context.Context) (string, error) {
+				list, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d namespace(s)", len(list.Items)), nil
+			},
+		},
+	}
+
+	permissions := []authorizationv1.ResourceAttributes{
+		{Namespace: ns, Verb: "list", Group: "apps", Resource: "deployments"},
+		{Namespace: ns, Verb: "watch", Group: "apps", Resource: "deployments"},
+		{Namespace: ns, Verb: "create", Group: "apps", Resource: "deployments"},
+		{Namespace: ns, Verb: "delete", Group: "apps", Resource: "deployments"},
+		{Namespace: ns, Verb: "list", Resource: "pods"},
+		{Namespace: ns, Verb: "delete", Resource: "pods"},
+	}
+	for _, attrs := range permissions {
+		checks = append(checks, doctorCheck{
+			Name: fmt.Sprintf("Can %s %s in '%s'", attrs.Verb, attrs.Resource, attrs.Namespace),
+			Run: func(ctx context.Context) (string, error) {
+				review := &authorizationv1.SelfSubjectAccessReview{
+					Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &attrs},
+				}
+				resp, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+				if err != nil {
+					return "", err
+				}
+				if !resp.Status.Allowed {
+					if resp.Status.Reason != "" {
+						return "", fmt.Errorf("denied: %s", resp.Status.Reason)
+					}
+					return "", fmt.Errorf("denied")
+				}
+				return "allowed", nil
+			},
+		})
+	}
+	return checks
+}
+
+func runDoctorChecks(ctx context.Context, checks []doctorCheck) []doctorResult {
+	results := make([]doctorResult, 0, len(checks))
+	for _, check := range checks {
+		start := time.Now()
+		detail, err := check.Run(ctx)
+		result := doctorResult{
+			Name:     check.Name,
+			Critical: check.Critical,
+			Passed:   err == nil,
+			Detail:   detail,
+			Latency:  time.Since(start),
+		}
+		if err != nil {
+			result.Detail = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func hasCriticalFailure(results []doctorResult) bool {
+	for _, r := range results {
+		if r.Critical && !r.Passed {
+			return true
+		}
+	}
+	return false
+}
+
+func printDoctorResults(out io.Writer, results []doctorResult) {
+	passed := 0
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "RESULT\tCHECK\tDETAIL\tLATENCY")
+	for _, r := range results {
+		status := "PASS"
+		if r.Passed {
+			passed++
+		} else if r.Critical {
+			status = "FAIL"
+		} else {
+			status = "WARN"
+		}
+		fmt.Fprintf(w, "[%s]\t%s\t%s\t%s\n", status, r.Name, r.Detail, r.Latency.Round(time.Millisecond))
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "\n%d/%d checks passed\n", passed, len(results))
+	if hasCriticalFailure(results) {
+		fmt.Fprintln(out, "One or more critical checks failed")
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().DurationVar(&doctorTimeout, "timeout", 30*time.Second, "Overall timeout for all checks")
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newDoctorTestClient(allowed bool) *fake.Clientset {
+	clientset := fake.NewClientset()
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.33.0"}
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = allowed
+		return true, review, nil
+	})
+	return clientset
+}
+
+func TestAPIDoctorChecks_AllPass(t *testing.T) {
+	results := runDoctorChecks(context.Background(), apiDoctorChecks(newDoctorTestClient(true), "default"))
+	for _, r := range results {
+		if !r.Passed {
+			t.Errorf("check %q failed: %s", r.Name, r.Detail)
+		}
+	}
+	if results[0].Detail != "v1.33.0" {
+		t.Errorf("version detail = %q, want v1.33.0", results[0].Detail)
+	}
+	if hasCriticalFailure(results) {
+		t.Error("expected no critical failure")
+	}
+}
+
+func TestAPIDoctorChecks_PermissionDeniedIsNotCritical(t *testing.T) {
+	results := runDoctorChecks(context.Background(), apiDoctorChecks(newDoctorTestClient(false), "default"))
+	if hasCriticalFailure(results) {
+		t.Error("denied permissions should not be a critical failure")
+	}
+
+	var buf bytes.Buffer
+	printDoctorResults(&buf, results)
+	if !strings.Contains(buf.String(), "[WARN]") {
+		t.Errorf("expected WARN entries in report, got:\n%s", buf.String())
+	}
+}
+
+func TestAPIDoctorChecks_CriticalFailure(t *testing.T) {
+	clientset := newDoctorTestClient(true)
+	clientset.PrependReactor("list", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection refused")
+	})
+
+	results := runDoctorChecks(context.Background(), apiDoctorChecks(clientset, "default"))
+	if !hasCriticalFailure(results) {
+		t.Error("expected critical failure when namespaces cannot be listed")
+	}
+
+	var buf bytes.Buffer
+	printDoctorResults(&buf, results)
+	if !strings.Contains(buf.String(), "[FAIL]") || !strings.Contains(buf.String(), "critical checks failed") {
+		t.Errorf("expected FAIL entry and summary in report, got:\n%s", buf.String())
+	}
+}
+
+func TestRunDoctor_InvalidKubeconfig(t *testing.T) {
+	originalKubeconfig := kubeconfig
+	defer func() { kubeconfig = originalKubeconfig }()
+	kubeconfig = "/invalid/path"
+
+	var buf bytes.Buffer
+	if runDoctor(context.Background(), &buf) {
+		t.Error("expected doctor to fail for invalid kubeconfig path")
+	}
+}
+
+func TestRunDoctor_TimeoutBoundsUnresponsiveServer(t *testing.T) {
+	hang := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { <-hang }))
+	defer server.Close()
+	defer close(hang)
+
+	path := filepath.Join(t.TempDir(), "config")
+	kubeconfigYAML := "apiVersion: v1\nkind: Config\ncurrent-context: hang\n" +
+		"clusters:\n- name: hang\n  cluster:\n    server: " + server.URL + "\n" +
+		"contexts:\n- name: hang\n  context:\n    cluster: hang\n    user: hang\n" +
+		"users:\n- name: hang\n  user:\n    token: secret\n"
+	if err := os.WriteFile(path, []byte(kubeconfigYAML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	originalKubeconfig := kubeconfig
+	defer func() { kubeconfig = originalKubeconfig }()
+	kubeconfig = path
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	var buf bytes.Buffer
+	start := time.Now()
+	if runDoctor(ctx, &buf) {
+		t.Errorf("expected doctor to fail against an unresponsive server, got:\n%s", buf.String())
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("runDoctor took %s despite a 200ms timeout", elapsed)
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+var errorFormat string
+
+// Exit codes returned for each class of failure, so wrapper scripts can branch on them.
+const (
+	exitGeneric     = 1
+	exitInvalid     = 2
+	exitConflict    = 3
+	exitNotFound    = 4
+	exitForbidden   = 5
+	exitTimeout     = 6
+	exitUnavailable = 7
+)
+
+// cliError is the object printed to stderr with --error-format json.
+type cliError struct {
+	Error    string `json:"error"`
+	Code     int    `json:"code"`
+	Resource string `json:"resource,omitempty"`
+}
+
+// exitWithError reports a failed command and exits with the code matching the error class.
+func exitWithError(msg string, err error) {
+	code := exitCodeFor(err)
+	if errorFormat == "json" {
+		writeErrorJSON(os.Stderr, msg, err)
+	} else {
+		log.Error().Err(err).Int("exit_code", code).Msg(msg)
+	}
+	os.Exit(code)
+}
+
+func writeErrorJSON(w io.Writer, msg string, err error) {
+	out := cliError{Error: msg, Code: exitCodeFor(err), Resource: errorResource(err)}
+	if err != nil {
+		out.Error = fmt.Sprintf("%s: %v", msg, err)
+	}
+	json.NewEncoder(w).Encode(out)
+}
+
+// exitCodeFor maps client-go API errors and transport failures to exit codes.
+func exitCodeFor(err error) int {
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return exitGeneric
+	case apierrors.IsNotFound(err):
+		return exitNotFound
+	case apierrors.IsForbidden(err), apierrors.IsUnauthorized(err):
+		return exitForbidden
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return exitTimeout
+	case apierrors.IsAlreadyExists(err), apierrors.IsConflict(err):
+		return exitConflict
+	case apierrors.IsInvalid(err), apierrors.IsBadRequest(err):
+		return exitInvalid
+	case apierrors.IsServiceUnavailable(err), apierrors.IsTooManyRequests(err):
+		return exitUnavailable
+	default:
+		return exitGeneric
+	}
+}
+
+// errorResource names the object an API error refers to, e.g. "deployments/web".
+func errorResource(err error) string {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || status.Status().Details == nil {
+		return ""
+	}
+	details := status.Status().Details
+	switch {
+	case details.Kind != "" && details.Name != "":
+		return details.Kind + "/" + details.Name
+	case details.Kind != "":
+		return details.Kind
+	default:
+		return details.Name
+	}
+}
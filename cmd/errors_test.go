@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestExitCodeFor(t *testing.T) {
+	deployments := schema.GroupResource{Group: "apps", Resource: "deployments"}
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"not found", apierrors.NewNotFound(deployments, "web"), exitNotFound},
+		{"wrapped not found", fmt.Errorf("failed to get deployment: %w", apierrors.NewNotFound(deployments, "web")), exitNotFound},
+		{"forbidden", apierrors.NewForbidden(deployments, "web", errors.New("denied")), exitForbidden},
+		{"unauthorized", apierrors.NewUnauthorized("bad token"), exitForbidden},
+		{"server timeout", apierrors.NewTimeoutError("slow", 1), exitTimeout},
+		{"deadline", fmt.Errorf("list: %w", context.DeadlineExceeded), exitTimeout},
+		{"already exists", apierrors.NewAlreadyExists(deployments, "web"), exitConflict},
+		{"bad request", apierrors.NewBadRequest("nope"), exitInvalid},
+		{"throttled", apierrors.NewTooManyRequests("slow down", 1), exitUnavailable},
+		{"other", errors.New("boom"), exitGeneric},
+	}
+	for _, tt := range tests {
+		if got := exitCodeFor(tt.err); got != tt.want {
+			t.Errorf("%s: exitCodeFor = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWriteErrorJSON(t *testing.T) {
+	var buf bytes.Buffer
+	err := fmt.Errorf("failed to delete deployment: %w", apierrors.NewNotFound(schema.GroupResource{Group: "apps", Resource: "deployments"}, "web"))
+	writeErrorJSON(&buf, "Failed to delete deployment", err)
+
+	var got cliError
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not JSON: %v (%s)", err, buf.String())
+	}
+	if got.Code != exitNotFound {
+		t.Errorf("code = %d, want %d", got.Code, exitNotFound)
+	}
+	if got.Resource != "deployments/web" {
+		t.Errorf("resource = %q, want %q", got.Resource, "deployments/web")
+	}
+	if got.Error == "" {
+		t.Error("expected error message to be set")
+	}
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var evictCmd = &cobra.Command{
+	Use:   "evict [pod]",
+	Short: "Evict a pod, honouring PodDisruptionBudgets",
+	Long: `Evict a pod through the eviction API instead of deleting it directly.
+The API server rejects the eviction when it would violate a PodDisruptionBudget.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		gracePeriod, _ := cmd.Flags().GetInt64("grace-period")
+
+		clientset, err := getKubeClient()
+		if err != nil {
+			exitWithError("Failed to create Kubernetes client", err)
+		}
+		err = evictPod(context.Background(), clientset, namespace, name, gracePeriod)
+		if apierrors.IsTooManyRequests(err) {
+			exitWithError("Eviction blocked by PodDisruptionBudget", err)
+		}
+		if err != nil {
+			exitWithError("Failed to evict pod", err)
+		}
+		fmt.Printf("Pod '%s' evicted successfully from namespace '%s'\n", name, namespace)
+	},
+}
+
+// evictPod requests an eviction for the pod. A negative gracePeriod keeps the pod's own
+// termination grace period. Evictions blocked by a disruption budget return a 429 error.
+func evictPod(ctx context.Context, clientset kubernetes.Interface, ns, name string, gracePeriod int64) error {
+	log.Info().Str("name", name).Str("namespace", ns).Int64("grace_period", gracePeriod).Msg("Evicting pod")
+
+	eviction := &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns}}
+	if gracePeriod >= 0 {
+		eviction.DeleteOptions = &metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod}
+	}
+	if err := clientset.PolicyV1().Evictions(ns).Evict(ctx, eviction); err != nil {
+		return fmt.Errorf("failed to evict pod: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(evictCmd)
+	evictCmd.Flags().Int64("grace-period", -1, "Seconds given to the pod to terminate gracefully (-1 uses the pod's own setting)")
+}
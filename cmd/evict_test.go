@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestEvictPod(t *testing.T) {
+	clientset := fake.NewClientset()
+	var got *policyv1.Eviction
+	clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		got = action.(k8stesting.CreateAction).GetObject().(*policyv1.Eviction)
+		return true, nil, nil
+	})
+
+	if err := evictPod(context.Background(), clientset, "default", "web-1", 5); err != nil {
+		t.Fatalf("evictPod returned error: %v", err)
+	}
+	if got == nil || got.Name != "web-1" || got.Namespace != "default" {
+		t.Fatalf("unexpected eviction sent: %+v", got)
+	}
+	if got.DeleteOptions == nil || *got.DeleteOptions.GracePeriodSeconds != 5 {
+		t.Errorf("expected grace period 5, got %+v", got.DeleteOptions)
+	}
+}
+
+func TestEvictPod_BlockedByBudget(t *testing.T) {
+	clientset := fake.NewClientset()
+	clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+	})
+
+	err := evictPod(context.Background(), clientset, "default", "web-1", -1)
+	if !apierrors.IsTooManyRequests(err) {
+		t.Errorf("expected 429 error, got %v", err)
+	}
+}
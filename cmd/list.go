@@ -3,27 +3,41 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
-	"text/tabwriter"
 	"time"
 
-	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/list"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 )
 
 var (
-	kubeconfig string
-	namespace  string
+	kubeconfig             string
+	kubeContext            string
+	labelSelector          string
+	namespace              string
+	createMissingNamespace bool
+	createIfNotExists      bool
+	fieldManager           string
 )
 
+// defaultFieldManager attributes objects written by this tool in managedFields.
+const defaultFieldManager = "k8s-controller"
+
 // Main commands
 var listCmd = &cobra.Command{
 	Use:   "list",
@@ -40,7 +54,19 @@ var createCmd = &cobra.Command{
 var deleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete Kubernetes resources",
-	Long:  "Delete various Kubernetes resources like deployments and pods",
+	Long:  "Delete various Kubernetes resources like deployments and pods, or every object in a manifest file with -f",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		filename, _ := cmd.Flags().GetString("filename")
+		if filename == "" {
+			_ = cmd.Help()
+			return
+		}
+		ignoreNotFound, _ := cmd.Flags().GetBool("ignore-not-found")
+		if err := deleteFromFile(filename, ignoreNotFound); err != nil {
+			exitWithError("Failed to delete resources from file", err)
+		}
+	},
 }
 
 // List subcommands
@@ -50,8 +76,7 @@ var listDeploymentsCmd = &cobra.Command{
 	Aliases: []string{"deploy", "deployment"},
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := listDeployments(); err != nil {
-			log.Error().Err(err).Msg("Failed to list deployments")
-			os.Exit(1)
+			exitWithError("Failed to list deployments", err)
 		}
 	},
 }
@@ -61,9 +86,9 @@ var listPodsCmd = &cobra.Command{
 	Short:   "List Kubernetes pods",
 	Aliases: []string{"pod", "po"},
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := listPods(); err != nil {
-			log.Error().Err(err).Msg("Failed to list pods")
-			os.Exit(1)
+		since, _ := cmd.Flags().GetDuration("since")
+		if err := listPods(since); err != nil {
+			exitWithError("Failed to list pods", err)
 		}
 	},
 }
@@ -73,14 +98,51 @@ var createDeploymentCmd = &cobra.Command{
 	Use:     "deployment [name] [image]",
 	Short:   "Create a Kubernetes deployment",
 	Aliases: []string{"deploy"},
-	Args:    cobra.ExactArgs(2),
+	Args:    cobra.RangeArgs(0, 2),
 	Run: func(cmd *cobra.Command, args []string) {
-		name := args[0]
-		image := args[1]
+		containers, err := containersFromFlags(cmd)
+		if err != nil {
+			exitWithError("Invalid containers", err)
+		}
+		name, image, err := resolveCreateArgs(args, containers)
+		if err != nil {
+			exitWithError("Invalid containers", err)
+		}
 		replicas, _ := cmd.Flags().GetInt32("replicas")
-		if err := createDeployment(name, image, replicas); err != nil {
-			log.Error().Err(err).Msg("Failed to create deployment")
-			os.Exit(1)
+		strategyType, _ := cmd.Flags().GetString("strategy")
+		maxSurge, _ := cmd.Flags().GetString("max-surge")
+		maxUnavailable, _ := cmd.Flags().GetString("max-unavailable")
+		strategy, err := parseDeploymentStrategy(strategyType, maxSurge, maxUnavailable)
+		if err != nil {
+			exitWithError("Invalid deployment strategy", err)
+		}
+		scheduling, err := schedulingFromFlags(cmd)
+		if err != nil {
+			exitWithError("Invalid scheduling options", err)
+		}
+		metadata, err := metadataFromFlags(cmd)
+		if err != nil {
+			exitWithError("Invalid metadata", err)
+		}
+		opts := deploymentOptions{Replicas: replicas, Strategy: strategy, Scheduling: scheduling, Containers: containers, Metadata: metadata}
+		if err := createDeployment(name, image, opts); err != nil {
+			exitWithError("Failed to create deployment", err)
+		}
+	},
+}
+
+var createNamespaceCmd = &cobra.Command{
+	Use:     "namespace [name]",
+	Short:   "Create a Kubernetes namespace",
+	Aliases: []string{"ns"},
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		metadata, err := metadataFromFlags(cmd)
+		if err != nil {
+			exitWithError("Invalid metadata", err)
+		}
+		if err := createNamespace(args[0], metadata); err != nil {
+			exitWithError("Failed to create namespace", err)
 		}
 	},
 }
@@ -89,13 +151,26 @@ var createPodCmd = &cobra.Command{
 	Use:     "pod [name] [image]",
 	Short:   "Create a Kubernetes pod",
 	Aliases: []string{"po"},
-	Args:    cobra.ExactArgs(2),
+	Args:    cobra.RangeArgs(0, 2),
 	Run: func(cmd *cobra.Command, args []string) {
-		name := args[0]
-		image := args[1]
-		if err := createPod(name, image); err != nil {
-			log.Error().Err(err).Msg("Failed to create pod")
-			os.Exit(1)
+		containers, err := containersFromFlags(cmd)
+		if err != nil {
+			exitWithError("Invalid containers", err)
+		}
+		name, image, err := resolveCreateArgs(args, containers)
+		if err != nil {
+			exitWithError("Invalid containers", err)
+		}
+		scheduling, err := schedulingFromFlags(cmd)
+		if err != nil {
+			exitWithError("Invalid scheduling options", err)
+		}
+		metadata, err := metadataFromFlags(cmd)
+		if err != nil {
+			exitWithError("Invalid metadata", err)
+		}
+		if err := createPod(name, image, scheduling, containers, metadata); err != nil {
+			exitWithError("Failed to create pod", err)
 		}
 	},
 }
@@ -109,8 +184,7 @@ var deleteDeploymentCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		name := args[0]
 		if err := deleteDeployment(name); err != nil {
-			log.Error().Err(err).Msg("Failed to delete deployment")
-			os.Exit(1)
+			exitWithError("Failed to delete deployment", err)
 		}
 	},
 }
@@ -123,127 +197,217 @@ var deletePodCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		name := args[0]
 		if err := deletePod(name); err != nil {
-			log.Error().Err(err).Msg("Failed to delete pod")
-			os.Exit(1)
+			exitWithError("Failed to delete pod", err)
 		}
 	},
 }
 
 // Helper functions
 func getKubeClient() (*kubernetes.Clientset, error) {
-	kubeconfigPath := getKubeconfigPath()
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	config, err := getKubeConfig()
 	if err != nil {
-		return nil, fmt.Errorf("failed to build config: %w", err)
+		return nil, err
 	}
 	return kubernetes.NewForConfig(config)
 }
 
-func getKubeconfigPath() string {
-	if kubeconfig != "" {
-		return kubeconfig
-	}
-	if kubeconfig := os.Getenv("KUBECONFIG"); kubeconfig != "" {
-		return kubeconfig
+func getKubeConfig() (*rest.Config, error) {
+	config, err := loadKubeConfig(kubeconfig, kubeContext)
+	if err != nil {
+		return nil, err
 	}
-	if home := homedir.HomeDir(); home != "" {
-		return filepath.Join(home, ".kube", "config")
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return newRetryTransport(rt, apiRetries)
+	})
+	return config, nil
+}
+
+// loadKubeConfig builds a client config with the standard client-go loading rules: an
+// explicit path wins, otherwise every file in the colon-separated KUBECONFIG is merged,
+// falling back to $HOME/.kube/config. A non-empty contextName overrides current-context.
+func loadKubeConfig(explicitPath, contextName string) (*rest.Config, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = explicitPath
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: contextName}
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build config: %w", err)
 	}
-	return ""
+	return config, nil
 }
 
 func listDeployments() error {
-	log.Info().Str("namespace", namespace).Msg("Listing deployments")
+	log.Info().Str("namespace", namespace).Bool("all_namespaces", allNamespaces).Str("selector", labelSelector).Msg("Listing deployments")
+
+	printer, err := newListPrinter()
+	if err != nil {
+		return err
+	}
 
 	clientset, err := getKubeClient()
 	if err != nil {
 		return err
 	}
 
-	deployments, err := clientset.AppsV1().Deployments(namespace).List(context.Background(), metav1.ListOptions{})
+	deployments, skipped, err := fetchDeployments(context.Background(), clientset)
 	if err != nil {
 		return fmt.Errorf("failed to list deployments: %w", err)
 	}
+	defer printSkippedNamespaces(os.Stderr, skipped)
 
-	if len(deployments.Items) == 0 {
-		fmt.Printf("No deployments found in namespace '%s'\n", namespace)
-		return nil
+	if printer != nil {
+		return printList(os.Stdout, printer, deployments, appsv1.SchemeGroupVersion.WithKind("Deployment"))
 	}
 
-	fmt.Printf("Found %d deployment(s) in namespace '%s':\n\n", len(deployments.Items), namespace)
+	objs := deploymentObjects(deployments)
+	if quietOutput {
+		return printNames(os.Stdout, objs, allNamespaces)
+	}
 
-	// Create tabwriter for formatted output
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-	fmt.Fprintln(w, "NAME\tREADY\tUP-TO-DATE\tAVAILABLE\tAGE")
+	if len(deployments.Items) == 0 {
+		fmt.Printf("No deployments found in %s\n", scopeDescription())
+		return nil
+	}
 
-	for _, deployment := range deployments.Items {
-		ready := fmt.Sprintf("%d/%d", deployment.Status.ReadyReplicas, deployment.Status.Replicas)
-		upToDate := fmt.Sprintf("%d", deployment.Status.UpdatedReplicas)
-		available := fmt.Sprintf("%d", deployment.Status.AvailableReplicas)
+	fmt.Printf("Found %d deployment(s) in %s:\n\n", len(deployments.Items), scopeDescription())
+	return list.PrintTable(os.Stdout, deploymentsResource, objs, allNamespaces)
+}
 
-		age := "unknown"
-		if !deployment.CreationTimestamp.Time.IsZero() {
-			age = formatAge(time.Since(deployment.CreationTimestamp.Time))
+// fetchDeployments lists the deployments selected by the namespace and selector flags.
+func fetchDeployments(ctx context.Context, clientset kubernetes.Interface) (*appsv1.DeploymentList, []namespaceFailure, error) {
+	deployments := &appsv1.DeploymentList{}
+	skipped, err := listInScope(ctx, clientset, func(ns string) error {
+		page, err := clientset.AppsV1().Deployments(ns).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			return err
 		}
+		deployments.Items = append(deployments.Items, page.Items...)
+		return nil
+	})
+	return deployments, skipped, err
+}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
-			deployment.Name, ready, upToDate, available, age)
+func deploymentObjects(deployments *appsv1.DeploymentList) []runtime.Object {
+	objs := make([]runtime.Object, 0, len(deployments.Items))
+	for i := range deployments.Items {
+		objs = append(objs, &deployments.Items[i])
 	}
-
-	w.Flush()
-	return nil
+	return objs
 }
 
-func listPods() error {
-	log.Info().Str("namespace", namespace).Msg("Listing pods")
+func listPods(since time.Duration) error {
+	log.Info().Str("namespace", namespace).Bool("all_namespaces", allNamespaces).Str("selector", labelSelector).Dur("since", since).Msg("Listing pods")
+
+	if since < 0 {
+		return fmt.Errorf("--since must not be negative, got %s", since)
+	}
+
+	printer, err := newListPrinter()
+	if err != nil {
+		return err
+	}
 
 	clientset, err := getKubeClient()
 	if err != nil {
 		return err
 	}
 
-	pods, err := clientset.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{})
+	pods, skipped, err := fetchPods(context.Background(), clientset)
 	if err != nil {
 		return fmt.Errorf("failed to list pods: %w", err)
 	}
+	defer printSkippedNamespaces(os.Stderr, skipped)
 
-	if len(pods.Items) == 0 {
-		fmt.Printf("No pods found in namespace '%s'\n", namespace)
-		return nil
+	if since > 0 {
+		pods.Items = filterPodsSince(pods.Items, since)
 	}
 
-	fmt.Printf("Found %d pod(s) in namespace '%s':\n\n", len(pods.Items), namespace)
+	if printer != nil {
+		return printList(os.Stdout, printer, pods, corev1.SchemeGroupVersion.WithKind("Pod"))
+	}
+
+	objs := podObjects(pods)
+	if quietOutput {
+		return printNames(os.Stdout, objs, allNamespaces)
+	}
 
-	// Create tabwriter for formatted output
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-	fmt.Fprintln(w, "NAME\tREADY\tSTATUS\tRESTARTS\tAGE")
+	if len(pods.Items) == 0 {
+		if since > 0 {
+			fmt.Printf("No pods created or restarted in the last %s in %s\n", since, scopeDescription())
+			return nil
+		}
+		fmt.Printf("No pods found in %s\n", scopeDescription())
+		return nil
+	}
 
-	for _, pod := range pods.Items {
-		ready := fmt.Sprintf("%d/%d", getPodReadyContainers(pod), len(pod.Spec.Containers))
-		status := string(pod.Status.Phase)
-		restarts := getPodRestartCount(pod)
+	fmt.Printf("Found %d pod(s) in %s:\n\n", len(pods.Items), scopeDescription())
+	return list.PrintTable(os.Stdout, podsResource, objs, allNamespaces)
+}
 
-		age := "unknown"
-		if !pod.CreationTimestamp.Time.IsZero() {
-			age = formatAge(time.Since(pod.CreationTimestamp.Time))
+// fetchPods lists the pods selected by the namespace and selector flags.
+func fetchPods(ctx context.Context, clientset kubernetes.Interface) (*corev1.PodList, []namespaceFailure, error) {
+	pods := &corev1.PodList{}
+	skipped, err := listInScope(ctx, clientset, func(ns string) error {
+		page, err := clientset.CoreV1().Pods(ns).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			return err
 		}
+		pods.Items = append(pods.Items, page.Items...)
+		return nil
+	})
+	return pods, skipped, err
+}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n",
-			pod.Name, ready, status, restarts, age)
+func podObjects(pods *corev1.PodList) []runtime.Object {
+	objs := make([]runtime.Object, 0, len(pods.Items))
+	for i := range pods.Items {
+		objs = append(objs, &pods.Items[i])
 	}
+	return objs
+}
 
-	w.Flush()
-	return nil
+// deploymentOptions holds the optional settings for createDeployment.
+type deploymentOptions struct {
+	Replicas   int32
+	Strategy   appsv1.DeploymentStrategy
+	Scheduling schedulingOptions
+	Containers containerOptions
+	Metadata   metadataOptions
 }
 
-func createDeployment(name, image string, replicas int32) error {
-	log.Info().Str("name", name).Str("image", image).Int32("replicas", replicas).Str("namespace", namespace).Msg("Creating deployment")
+func createDeployment(name, image string, opts deploymentOptions) error {
+	log.Info().Str("name", name).Str("image", image).Int32("replicas", opts.Replicas).Str("namespace", namespace).Msg("Creating deployment")
 
 	clientset, err := getKubeClient()
 	if err != nil {
 		return err
 	}
 
+	if err := ensureNamespace(context.Background(), clientset, namespace, createMissingNamespace); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	deployments := clientset.AppsV1().Deployments(namespace)
+	created, err := createOnce(os.Stdout, fmt.Sprintf("Deployment '%s' in namespace '%s'", name, namespace), func() error {
+		_, err := deployments.Get(ctx, name, metav1.GetOptions{})
+		return err
+	}, func() error {
+		_, err := deployments.Create(ctx, buildDeployment(name, image, opts), metav1.CreateOptions{FieldManager: fieldManager})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create deployment: %w", err)
+	}
+
+	if created {
+		fmt.Printf("Deployment '%s' created successfully in namespace '%s'\n", name, namespace)
+	}
+	return nil
+}
+
+func buildDeployment(name, image string, opts deploymentOptions) *appsv1.Deployment {
+	replicas := opts.Replicas
 	deployment := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
@@ -251,6 +415,7 @@ func createDeployment(name, image string, replicas int32) error {
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: &replicas,
+			Strategy: opts.Strategy,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: map[string]string{
 					"app": name,
@@ -263,33 +428,163 @@ func createDeployment(name, image string, replicas int32) error {
 					},
 				},
 				Spec: corev1.PodSpec{
-					Containers: []corev1.Container{
-						{
-							Name:  name,
-							Image: image,
-							Ports: []corev1.ContainerPort{
-								{
-									ContainerPort: 80,
-									Protocol:      corev1.ProtocolTCP,
-								},
-							},
-						},
-					},
+					Containers: primaryContainer(name, image),
 				},
 			},
 		},
 	}
+	opts.Containers.apply(&deployment.Spec.Template.Spec)
+	opts.Scheduling.apply(&deployment.Spec.Template.Spec)
+	opts.Metadata.apply(&deployment.ObjectMeta)
+	// The pods get the labels too, and the selector follows an overridden app label.
+	template := &deployment.Spec.Template.ObjectMeta
+	template.Labels = mergeStringMaps(template.Labels, opts.Metadata.Labels)
+	deployment.Spec.Selector.MatchLabels["app"] = template.Labels["app"]
+	return deployment
+}
+
+// parseDeploymentStrategy builds a deployment strategy from the --strategy, --max-surge and
+// --max-unavailable flags. Empty values leave the API server defaults in place.
+func parseDeploymentStrategy(strategyType, maxSurge, maxUnavailable string) (appsv1.DeploymentStrategy, error) {
+	var strategy appsv1.DeploymentStrategy
+	switch {
+	case strategyType == "":
+	case strings.EqualFold(strategyType, string(appsv1.RollingUpdateDeploymentStrategyType)):
+		strategy.Type = appsv1.RollingUpdateDeploymentStrategyType
+	case strings.EqualFold(strategyType, string(appsv1.RecreateDeploymentStrategyType)):
+		strategy.Type = appsv1.RecreateDeploymentStrategyType
+	default:
+		return strategy, fmt.Errorf("unknown strategy %q, must be RollingUpdate or Recreate", strategyType)
+	}
 
-	_, err = clientset.AppsV1().Deployments(namespace).Create(context.Background(), deployment, metav1.CreateOptions{})
+	if maxSurge == "" && maxUnavailable == "" {
+		return strategy, nil
+	}
+	if strategy.Type == appsv1.RecreateDeploymentStrategyType {
+		return strategy, fmt.Errorf("--max-surge and --max-unavailable can only be used with the RollingUpdate strategy")
+	}
+
+	rollingUpdate := &appsv1.RollingUpdateDeployment{}
+	var err error
+	if rollingUpdate.MaxSurge, err = parseIntOrPercent("max-surge", maxSurge); err != nil {
+		return strategy, err
+	}
+	if rollingUpdate.MaxUnavailable, err = parseIntOrPercent("max-unavailable", maxUnavailable); err != nil {
+		return strategy, err
+	}
+	if isZeroIntOrPercent(rollingUpdate.MaxSurge) && isZeroIntOrPercent(rollingUpdate.MaxUnavailable) {
+		return strategy, fmt.Errorf("--max-surge and --max-unavailable cannot both be 0")
+	}
+	strategy.Type = appsv1.RollingUpdateDeploymentStrategyType
+	strategy.RollingUpdate = rollingUpdate
+	return strategy, nil
+}
+
+// parseIntOrPercent accepts a non-negative integer or a percentage between 0% and 100%.
+func parseIntOrPercent(flag, value string) (*intstr.IntOrString, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if percent, ok := strings.CutSuffix(value, "%"); ok {
+		n, err := strconv.Atoi(percent)
+		if err != nil || n < 0 || n > 100 {
+			return nil, fmt.Errorf("invalid --%s %q: percentage must be between 0%% and 100%%", flag, value)
+		}
+		v := intstr.FromString(value)
+		return &v, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid --%s %q: must be a non-negative integer or a percentage", flag, value)
+	}
+	v := intstr.FromInt32(int32(n))
+	return &v, nil
+}
+
+func isZeroIntOrPercent(v *intstr.IntOrString) bool {
+	return v != nil && (v.String() == "0" || v.String() == "0%")
+}
+
+func createNamespace(name string, metadata metadataOptions) error {
+	log.Info().Str("name", name).Msg("Creating namespace")
+
+	clientset, err := getKubeClient()
 	if err != nil {
-		return fmt.Errorf("failed to create deployment: %w", err)
+		return err
+	}
+
+	ctx := context.Background()
+	namespaces := clientset.CoreV1().Namespaces()
+	created, err := createOnce(os.Stdout, fmt.Sprintf("Namespace '%s'", name), func() error {
+		_, err := namespaces.Get(ctx, name, metav1.GetOptions{})
+		return err
+	}, func() error {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		metadata.apply(&ns.ObjectMeta)
+		_, err := namespaces.Create(ctx, ns, metav1.CreateOptions{FieldManager: fieldManager})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create namespace: %w", err)
 	}
 
-	fmt.Printf("Deployment '%s' created successfully in namespace '%s'\n", name, namespace)
+	if created {
+		fmt.Printf("Namespace '%s' created successfully\n", name)
+	}
 	return nil
 }
 
-func createPod(name, image string) error {
+// ensureNamespace checks that ns exists before objects are created in it, creating it
+// when create is set. Users who may not read namespaces skip the check and let the
+// API server report the outcome of the create itself.
+func ensureNamespace(ctx context.Context, clientset kubernetes.Interface, ns string, create bool) error {
+	_, err := clientset.CoreV1().Namespaces().Get(ctx, ns, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		return nil
+	case apierrors.IsForbidden(err):
+		log.Debug().Err(err).Str("namespace", ns).Msg("Cannot read namespace, skipping existence check")
+		return nil
+	case !apierrors.IsNotFound(err):
+		return fmt.Errorf("failed to check namespace: %w", err)
+	case !create:
+		return fmt.Errorf("namespace %s does not exist; create it with `create namespace %s` or pass --create-namespace", ns, ns)
+	}
+
+	log.Info().Str("namespace", ns).Msg("Creating missing namespace")
+	_, err = clientset.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}}, metav1.CreateOptions{FieldManager: fieldManager})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create namespace: %w", err)
+	}
+	if err == nil {
+		fmt.Printf("Namespace '%s' created\n", ns)
+	}
+	return nil
+}
+
+// createOnce runs create and reports whether it created the object. With --if-not-exists
+// the object is looked up with get first and creation is skipped when it exists, also
+// when it appears between the two calls; description names it in the skip message.
+func createOnce(out io.Writer, description string, get, create func() error) (bool, error) {
+	if createIfNotExists {
+		err := get()
+		switch {
+		case err == nil:
+			fmt.Fprintf(out, "%s already exists, skipping\n", description)
+			return false, nil
+		case !apierrors.IsNotFound(err):
+			return false, err
+		}
+	}
+	err := create()
+	if createIfNotExists && apierrors.IsAlreadyExists(err) {
+		fmt.Fprintf(out, "%s already exists, skipping\n", description)
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func createPod(name, image string, scheduling schedulingOptions, containers containerOptions, metadata metadataOptions) error {
 	log.Info().Str("name", name).Str("image", image).Str("namespace", namespace).Msg("Creating pod")
 
 	clientset, err := getKubeClient()
@@ -297,6 +592,32 @@ func createPod(name, image string) error {
 		return err
 	}
 
+	if err := ensureNamespace(context.Background(), clientset, namespace, createMissingNamespace); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	pods := clientset.CoreV1().Pods(namespace)
+	created, err := createOnce(os.Stdout, fmt.Sprintf("Pod '%s' in namespace '%s'", name, namespace), func() error {
+		_, err := pods.Get(ctx, name, metav1.GetOptions{})
+		return err
+	}, func() error {
+		pod := buildPod(name, image, scheduling, containers)
+		metadata.apply(&pod.ObjectMeta)
+		_, err := pods.Create(ctx, pod, metav1.CreateOptions{FieldManager: fieldManager})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create pod: %w", err)
+	}
+
+	if created {
+		fmt.Printf("Pod '%s' created successfully in namespace '%s'\n", name, namespace)
+	}
+	return nil
+}
+
+func buildPod(name, image string, scheduling schedulingOptions, containers containerOptions) *corev1.Pod {
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
@@ -306,28 +627,12 @@ func createPod(name, image string) error {
 			},
 		},
 		Spec: corev1.PodSpec{
-			Containers: []corev1.Container{
-				{
-					Name:  name,
-					Image: image,
-					Ports: []corev1.ContainerPort{
-						{
-							ContainerPort: 80,
-							Protocol:      corev1.ProtocolTCP,
-						},
-					},
-				},
-			},
+			Containers: primaryContainer(name, image),
 		},
 	}
-
-	_, err = clientset.CoreV1().Pods(namespace).Create(context.Background(), pod, metav1.CreateOptions{})
-	if err != nil {
-		return fmt.Errorf("failed to create pod: %w", err)
-	}
-
-	fmt.Printf("Pod '%s' created successfully in namespace '%s'\n", name, namespace)
-	return nil
+	containers.apply(&pod.Spec)
+	scheduling.apply(&pod.Spec)
+	return pod
 }
 
 func deleteDeployment(name string) error {
@@ -383,6 +688,57 @@ func getPodRestartCount(pod corev1.Pod) int32 {
 	return restarts
 }
 
+// getPodLastTermination returns the most recent previous termination across the pod's
+// containers together with the container name, or nil if no container has restarted.
+func getPodLastTermination(pod corev1.Pod) (*corev1.ContainerStateTerminated, string) {
+	var last *corev1.ContainerStateTerminated
+	var container string
+	for _, status := range pod.Status.ContainerStatuses {
+		terminated := status.LastTerminationState.Terminated
+		if terminated == nil {
+			continue
+		}
+		if last == nil || terminated.FinishedAt.After(last.FinishedAt.Time) {
+			last = terminated
+			container = status.Name
+		}
+	}
+	return last, container
+}
+
+// filterPodsSince keeps the pods created or restarted within the window.
+func filterPodsSince(pods []corev1.Pod, window time.Duration) []corev1.Pod {
+	var recent []corev1.Pod
+	for _, pod := range pods {
+		if withinWindow(getPodActivityTime(pod), window) {
+			recent = append(recent, pod)
+		}
+	}
+	return recent
+}
+
+// getPodActivityTime returns the latest of the pod's creation time and its containers' last restart.
+func getPodActivityTime(pod corev1.Pod) time.Time {
+	latest := pod.CreationTimestamp.Time
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.RestartCount == 0 {
+			continue
+		}
+		if running := status.State.Running; running != nil && running.StartedAt.Time.After(latest) {
+			latest = running.StartedAt.Time
+		}
+		if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.FinishedAt.Time.After(latest) {
+			latest = terminated.FinishedAt.Time
+		}
+	}
+	return latest
+}
+
+// withinWindow reports whether t is no older than window. Zero (unknown) timestamps never match.
+func withinWindow(t time.Time, window time.Duration) bool {
+	return !t.IsZero() && time.Since(t) <= window
+}
+
 func formatAge(duration time.Duration) string {
 	days := int(duration.Hours() / 24)
 	hours := int(duration.Hours()) % 24
@@ -412,18 +768,49 @@ func init() {
 	// Add subcommands to create
 	createCmd.AddCommand(createDeploymentCmd)
 	createCmd.AddCommand(createPodCmd)
+	createCmd.AddCommand(createNamespaceCmd)
 
 	// Add subcommands to delete
 	deleteCmd.AddCommand(deleteDeploymentCmd)
 	deleteCmd.AddCommand(deletePodCmd)
 
 	// Global flags for all commands
-	persistentFlags := []*cobra.Command{listCmd, createCmd, deleteCmd}
+	persistentFlags := []*cobra.Command{listCmd, createCmd, deleteCmd, doctorCmd, logsCmd, evictCmd, triageCmd, rolloutCmd, setCmd, waitCmd, validateCmd, watchCmd, babysitCmd}
 	for _, cmd := range persistentFlags {
-		cmd.PersistentFlags().StringVarP(&kubeconfig, "kubeconfig", "k", "", "Path to the kubeconfig file (default: $HOME/.kube/config)")
+		cmd.PersistentFlags().StringVarP(&kubeconfig, "kubeconfig", "k", "", "Path to the kubeconfig file (default: the merged KUBECONFIG files, then $HOME/.kube/config)")
+		cmd.PersistentFlags().StringVar(&kubeContext, "context", "", "Kubeconfig context to use (default: current-context)")
 		cmd.PersistentFlags().StringVarP(&namespace, "namespace", "n", "default", "Kubernetes namespace")
+		cmd.PersistentFlags().IntVar(&apiRetries, "retries", 3, "Times to retry API requests rejected with 429 or 503 and a Retry-After, honouring it up to 30s (0 disables)")
 	}
 
+	// Specific flags for list
+	listCmd.PersistentFlags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "List across all namespaces, skipping namespaces the caller may not read")
+	listCmd.PersistentFlags().StringVarP(&labelSelector, "selector", "l", "", "Label selector to filter on, e.g. app=web,tier!=cache")
+	listCmd.PersistentFlags().BoolVarP(&quietOutput, "quiet", "q", false, "Print only resource names, one per line (namespace/name with --all-namespaces)")
+	listCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "", "Output format: go-template=TEMPLATE or jsonpath=EXPRESSION (default: table)")
+
+	// Specific flags for list pods
+	listPodsCmd.Flags().Duration("since", 0, "Only show pods created or restarted within this duration (e.g. 10m)")
+
+	// Specific flags for delete
+	deleteCmd.Flags().StringP("filename", "f", "", "Manifest file with the objects to delete ('-' reads from stdin)")
+	deleteCmd.Flags().Bool("ignore-not-found", true, "Treat objects that no longer exist as already deleted")
+
+	// Specific flags for create
+	createCmd.PersistentFlags().BoolVar(&createMissingNamespace, "create-namespace", false, "Create the target namespace if it does not exist")
+	createCmd.PersistentFlags().BoolVar(&createIfNotExists, "if-not-exists", false, "Skip creation, without an error, when an object with the same name already exists")
+	addMetadataFlags(createCmd)
+	createCmd.PersistentFlags().StringVar(&fieldManager, "field-manager", defaultFieldManager, "Name recorded as the manager of written fields in managedFields")
+
 	// Specific flags for create deployment
 	createDeploymentCmd.Flags().Int32P("replicas", "r", 1, "Number of replicas for the deployment")
+	createDeploymentCmd.Flags().String("strategy", "", "Update strategy: RollingUpdate or Recreate (default: RollingUpdate)")
+	createDeploymentCmd.Flags().String("max-surge", "", "Maximum pods above the desired count during a rolling update (e.g. 1 or 25%)")
+	createDeploymentCmd.Flags().String("max-unavailable", "", "Maximum unavailable pods during a rolling update (e.g. 0 or 25%)")
+	addSchedulingFlags(createDeploymentCmd)
+	addContainerFlags(createDeploymentCmd)
+
+	// Specific flags for create pod
+	addSchedulingFlags(createPodCmd)
+	addContainerFlags(createPodCmd)
 }
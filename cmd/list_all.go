@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/list"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+)
+
+var listServicesCmd = &cobra.Command{
+	Use:     "services",
+	Short:   "List Kubernetes services",
+	Aliases: []string{"service", "svc"},
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := listServices(); err != nil {
+			exitWithError("Failed to list services", err)
+		}
+	},
+}
+
+var listAllCmd = &cobra.Command{
+	Use:   "all",
+	Short: "List deployments, pods and services in one overview",
+	Long:  "List deployments, pods and services together, fetched concurrently and printed in one section per resource",
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := listAll(); err != nil {
+			exitWithError("Failed to list resources", err)
+		}
+	},
+}
+
+func listServices() error {
+	log.Info().Str("namespace", namespace).Bool("all_namespaces", allNamespaces).Str("selector", labelSelector).Msg("Listing services")
+
+	printer, err := newListPrinter()
+	if err != nil {
+		return err
+	}
+
+	clientset, err := getKubeClient()
+	if err != nil {
+		return err
+	}
+
+	services, skipped, err := fetchServices(context.Background(), clientset)
+	if err != nil {
+		return fmt.Errorf("failed to list services: %w", err)
+	}
+	defer printSkippedNamespaces(os.Stderr, skipped)
+
+	if printer != nil {
+		return printList(os.Stdout, printer, services, corev1.SchemeGroupVersion.WithKind("Service"))
+	}
+
+	objs := serviceObjects(services)
+	if quietOutput {
+		return printNames(os.Stdout, objs, allNamespaces)
+	}
+
+	if len(services.Items) == 0 {
+		fmt.Printf("No services found in %s\n", scopeDescription())
+		return nil
+	}
+
+	fmt.Printf("Found %d service(s) in %s:\n\n", len(services.Items), scopeDescription())
+	return list.PrintTable(os.Stdout, servicesResource, objs, allNamespaces)
+}
+
+// fetchServices lists the services selected by the namespace and selector flags.
+func fetchServices(ctx context.Context, clientset kubernetes.Interface) (*corev1.ServiceList, []namespaceFailure, error) {
+	services := &corev1.ServiceList{}
+	skipped, err := listInScope(ctx, clientset, func(ns string) error {
+		page, err := clientset.CoreV1().Services(ns).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			return err
+		}
+		services.Items = append(services.Items, page.Items...)
+		return nil
+	})
+	return services, skipped, err
+}
+
+func serviceObjects(services *corev1.ServiceList) []runtime.Object {
+	objs := make([]runtime.Object, 0, len(services.Items))
+	for i := range services.Items {
+		objs = append(objs, &services.Items[i])
+	}
+	return objs
+}
+
+// resourceSection is one resource type of the list all overview.
+type resourceSection struct {
+	Title    string
+	Resource string
+	GVK      schema.GroupVersionKind
+	List     runtime.Object
+	Objects  []runtime.Object
+	Skipped  []namespaceFailure
+	Err      error
+}
+
+func listAll() error {
+	log.Info().Str("namespace", namespace).Bool("all_namespaces", allNamespaces).Str("selector", labelSelector).Msg("Listing all resources")
+
+	printer, err := newListPrinter()
+	if err != nil {
+		return err
+	}
+	if quietOutput {
+		return errors.New("--quiet is not supported by list all, list a single resource type instead")
+	}
+
+	clientset, err := getKubeClient()
+	if err != nil {
+		return err
+	}
+
+	sections := fetchAll(context.Background(), clientset)
+	var skipped []namespaceFailure
+	seen := map[string]bool{}
+	for _, section := range sections {
+		if section.Err != nil {
+			return fmt.Errorf("failed to list %s: %w", section.Resource, section.Err)
+		}
+		for _, s := range section.Skipped {
+			if !seen[s.Namespace] {
+				seen[s.Namespace] = true
+				skipped = append(skipped, s)
+			}
+		}
+	}
+	defer printSkippedNamespaces(os.Stderr, skipped)
+
+	if printer != nil {
+		return printAllList(os.Stdout, printer, sections)
+	}
+	return printAllSections(os.Stdout, sections, allNamespaces)
+}
+
+// fetchAll lists deployments, pods and services concurrently, returning the sections in
+// a fixed order. Errors are recorded per section.
+func fetchAll(ctx context.Context, clientset kubernetes.Interface) []resourceSection {
+	sections := []resourceSection{
+		{Title: "Deployments", Resource: deploymentsResource, GVK: appsv1.SchemeGroupVersion.WithKind("Deployment")},
+		{Title: "Pods", Resource: podsResource, GVK: corev1.SchemeGroupVersion.WithKind("Pod")},
+		{Title: "Services", Resource: servicesResource, GVK: corev1.SchemeGroupVersion.WithKind("Service")},
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(sections))
+	go func() {
+		defer wg.Done()
+		deployments, skipped, err := fetchDeployments(ctx, clientset)
+		sections[0].List, sections[0].Objects, sections[0].Skipped, sections[0].Err = deployments, deploymentObjects(deployments), skipped, err
+	}()
+	go func() {
+		defer wg.Done()
+		pods, skipped, err := fetchPods(ctx, clientset)
+		sections[1].List, sections[1].Objects, sections[1].Skipped, sections[1].Err = pods, podObjects(pods), skipped, err
+	}()
+	go func() {
+		defer wg.Done()
+		services, skipped, err := fetchServices(ctx, clientset)
+		sections[2].List, sections[2].Objects, sections[2].Skipped, sections[2].Err = services, serviceObjects(services), skipped, err
+	}()
+	wg.Wait()
+	return sections
+}
+
+// printAllSections writes a titled table per non-empty section.
+func printAllSections(w io.Writer, sections []resourceSection, withNamespace bool) error {
+	printed := false
+	for _, section := range sections {
+		if len(section.Objects) == 0 {
+			continue
+		}
+		if printed {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s (%d):\n", section.Title, len(section.Objects))
+		if err := list.PrintTable(w, section.Resource, section.Objects, withNamespace); err != nil {
+			return err
+		}
+		printed = true
+	}
+	if !printed {
+		fmt.Fprintf(w, "No resources found in %s\n", scopeDescription())
+	}
+	return nil
+}
+
+// printAllList renders every section as a single List of mixed kinds, like kubectl get all -o.
+func printAllList(w io.Writer, printer outputPrinter, sections []resourceSection) error {
+	items := []interface{}{}
+	for _, section := range sections {
+		data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(section.List)
+		if err != nil {
+			return fmt.Errorf("failed to convert %s: %w", section.Resource, err)
+		}
+		items = append(items, listItems(data, section.GVK)...)
+	}
+	return printer(w, map[string]interface{}{"apiVersion": "v1", "kind": "List", "items": items})
+}
+
+func init() {
+	listCmd.AddCommand(listServicesCmd)
+	listCmd.AddCommand(listAllCmd)
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newListAllClientset() *fake.Clientset {
+	labels := map[string]string{"app": "web"}
+	return fake.NewClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Labels: labels}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default", Labels: labels}},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Labels: labels},
+			Spec: corev1.ServiceSpec{
+				Type:      corev1.ServiceTypeNodePort,
+				ClusterIP: "10.0.0.10",
+				Ports:     []corev1.ServicePort{{Port: 80, NodePort: 30080, Protocol: corev1.ProtocolTCP}},
+			},
+		},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "staging"}},
+	)
+}
+
+func TestFetchAll_RespectsNamespaceAndSelector(t *testing.T) {
+	originalNamespace, originalSelector := namespace, labelSelector
+	defer func() { namespace, labelSelector = originalNamespace, originalSelector }()
+	namespace, labelSelector = "default", "app=web"
+
+	sections := fetchAll(context.Background(), newListAllClientset())
+	if len(sections) != 3 {
+		t.Fatalf("got %d sections, want 3", len(sections))
+	}
+	for _, section := range sections {
+		if section.Err != nil {
+			t.Fatalf("%s: unexpected error: %v", section.Resource, section.Err)
+		}
+		if len(section.Objects) != 1 {
+			t.Errorf("%s: got %d objects, want 1", section.Resource, len(section.Objects))
+		}
+	}
+}
+
+func TestPrintAllSections(t *testing.T) {
+	originalNamespace := namespace
+	defer func() { namespace = originalNamespace }()
+	namespace = "default"
+
+	sections := fetchAll(context.Background(), newListAllClientset())
+	var out bytes.Buffer
+	if err := printAllSections(&out, sections, false); err != nil {
+		t.Fatalf("printAllSections returned error: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{"Deployments (2):", "Pods (1):", "Services (1):", "80:30080/TCP", "10.0.0.10"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Index(got, "Deployments") > strings.Index(got, "Pods") || strings.Index(got, "Pods") > strings.Index(got, "Services") {
+		t.Errorf("sections out of order:\n%s", got)
+	}
+
+	out.Reset()
+	namespace = "empty"
+	if err := printAllSections(&out, fetchAll(context.Background(), newListAllClientset()), false); err != nil {
+		t.Fatalf("printAllSections returned error: %v", err)
+	}
+	if got := out.String(); got != "No resources found in namespace 'empty'\n" {
+		t.Errorf("empty output = %q", got)
+	}
+}
+
+func TestPrintAllList(t *testing.T) {
+	originalNamespace := namespace
+	defer func() { namespace = originalNamespace }()
+	namespace = "default"
+
+	printer, err := newOutputPrinter(`jsonpath={.kind}{range .items[*]}{" "}{.apiVersion}{"/"}{.kind}{"/"}{.metadata.name}{end}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := printAllList(&out, printer, fetchAll(context.Background(), newListAllClientset())); err != nil {
+		t.Fatalf("printAllList returned error: %v", err)
+	}
+
+	want := "List apps/v1/Deployment/db apps/v1/Deployment/web v1/Pod/web-1 v1/Service/web"
+	if got := out.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/list"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Resource names the list tables are registered under, see list.RegisterColumns.
+const (
+	deploymentsResource = "deployments"
+	podsResource        = "pods"
+	servicesResource    = "services"
+)
+
+// deploymentColumn and podColumn adapt typed extractors to list.Column.
+func deploymentColumn(header string, value func(*appsv1.Deployment) string) list.Column {
+	return list.Column{Header: header, Value: func(obj runtime.Object) string { return value(obj.(*appsv1.Deployment)) }}
+}
+
+func podColumn(header string, value func(*corev1.Pod) string) list.Column {
+	return list.Column{Header: header, Value: func(obj runtime.Object) string { return value(obj.(*corev1.Pod)) }}
+}
+
+func serviceColumn(header string, value func(*corev1.Service) string) list.Column {
+	return list.Column{Header: header, Value: func(obj runtime.Object) string { return value(obj.(*corev1.Service)) }}
+}
+
+// servicePorts formats ports like kubectl, e.g. "80/TCP,443:30443/TCP".
+func servicePorts(svc *corev1.Service) string {
+	if len(svc.Spec.Ports) == 0 {
+		return "<none>"
+	}
+	ports := make([]string, 0, len(svc.Spec.Ports))
+	for _, p := range svc.Spec.Ports {
+		port := fmt.Sprintf("%d", p.Port)
+		if p.NodePort != 0 {
+			port += fmt.Sprintf(":%d", p.NodePort)
+		}
+		ports = append(ports, port+"/"+string(p.Protocol))
+	}
+	return strings.Join(ports, ",")
+}
+
+func objectAge(created metav1.Time) string {
+	if created.Time.IsZero() {
+		return "unknown"
+	}
+	return formatAge(time.Since(created.Time))
+}
+
+func init() {
+	list.SetDefaultColumns(deploymentsResource, []list.Column{
+		deploymentColumn("NAME", func(d *appsv1.Deployment) string { return d.Name }),
+		deploymentColumn("READY", func(d *appsv1.Deployment) string {
+			return fmt.Sprintf("%d/%d", d.Status.ReadyReplicas, d.Status.Replicas)
+		}),
+		deploymentColumn("UP-TO-DATE", func(d *appsv1.Deployment) string { return fmt.Sprintf("%d", d.Status.UpdatedReplicas) }),
+		deploymentColumn("AVAILABLE", func(d *appsv1.Deployment) string { return fmt.Sprintf("%d", d.Status.AvailableReplicas) }),
+		deploymentColumn("AGE", func(d *appsv1.Deployment) string { return objectAge(d.CreationTimestamp) }),
+	})
+	list.SetDefaultColumns(podsResource, []list.Column{
+		podColumn("NAME", func(p *corev1.Pod) string { return p.Name }),
+		podColumn("READY", func(p *corev1.Pod) string {
+			return fmt.Sprintf("%d/%d", getPodReadyContainers(*p), len(p.Spec.Containers))
+		}),
+		podColumn("STATUS", func(p *corev1.Pod) string { return string(p.Status.Phase) }),
+		podColumn("RESTARTS", func(p *corev1.Pod) string { return fmt.Sprintf("%d", getPodRestartCount(*p)) }),
+		podColumn("AGE", func(p *corev1.Pod) string { return objectAge(p.CreationTimestamp) }),
+	})
+	list.SetDefaultColumns(servicesResource, []list.Column{
+		serviceColumn("NAME", func(svc *corev1.Service) string { return svc.Name }),
+		serviceColumn("TYPE", func(svc *corev1.Service) string { return string(svc.Spec.Type) }),
+		serviceColumn("CLUSTER-IP", func(svc *corev1.Service) string { return orNone(svc.Spec.ClusterIP) }),
+		serviceColumn("PORT(S)", servicePorts),
+		serviceColumn("AGE", func(svc *corev1.Service) string { return objectAge(svc.CreationTimestamp) }),
+	})
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/rs/zerolog/log"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var allNamespaces bool
+
+// namespaceFailure records a namespace skipped while listing across namespaces.
+type namespaceFailure struct {
+	Namespace string
+	Err       error
+}
+
+// listInScope calls list for the namespace selected by flags. With --all-namespaces it
+// tries a cluster-wide list first; when RBAC forbids that, every namespace is listed on
+// its own and the ones that are denied are returned instead of failing the whole list.
+// An error is returned only if nothing could be listed.
+func listInScope(ctx context.Context, clientset kubernetes.Interface, list func(ns string) error) ([]namespaceFailure, error) {
+	if !allNamespaces {
+		return nil, list(namespace)
+	}
+
+	err := list(metav1.NamespaceAll)
+	if err == nil || !apierrors.IsForbidden(err) {
+		return nil, err
+	}
+	log.Debug().Err(err).Msg("Cluster-wide list forbidden, listing namespaces one by one")
+
+	namespaces, nsErr := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if nsErr != nil {
+		return nil, fmt.Errorf("cannot list across namespaces: %w", err)
+	}
+
+	var skipped []namespaceFailure
+	for _, ns := range namespaces.Items {
+		if err := list(ns.Name); err != nil {
+			if !apierrors.IsForbidden(err) {
+				return nil, err
+			}
+			skipped = append(skipped, namespaceFailure{Namespace: ns.Name, Err: err})
+		}
+	}
+	if len(skipped) > 0 && len(skipped) == len(namespaces.Items) {
+		return nil, fmt.Errorf("access denied in all %d namespaces: %w", len(skipped), skipped[0].Err)
+	}
+	return skipped, nil
+}
+
+// scopeDescription names the listed scope for messages, e.g. "namespace 'default'".
+func scopeDescription() string {
+	if allNamespaces {
+		return "all namespaces"
+	}
+	return fmt.Sprintf("namespace '%s'", namespace)
+}
+
+// printSkippedNamespaces writes the footer listing namespaces left out of a partial result.
+func printSkippedNamespaces(w io.Writer, skipped []namespaceFailure) {
+	if len(skipped) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\nSkipped %d namespace(s) due to permissions:\n", len(skipped))
+	for _, s := range skipped {
+		fmt.Fprintf(w, "  %s: %v\n", s.Namespace, s.Err)
+	}
+}
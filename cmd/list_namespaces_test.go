@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newPartialAccessClientset forbids cluster-wide deployment lists and lists in the denied namespaces.
+func newPartialAccessClientset(namespaces []string, denied ...string) *fake.Clientset {
+	var objects []runtime.Object
+	for _, ns := range namespaces {
+		objects = append(objects,
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: ns}},
+		)
+	}
+	clientset := fake.NewClientset(objects...)
+	clientset.PrependReactor("list", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		ns := action.GetNamespace()
+		if ns == "" || slices.Contains(denied, ns) {
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, "", nil)
+		}
+		return false, nil, nil
+	})
+	return clientset
+}
+
+func listTestDeployments(clientset *fake.Clientset) ([]string, []namespaceFailure, error) {
+	ctx := context.Background()
+	var found []string
+	skipped, err := listInScope(ctx, clientset, func(ns string) error {
+		list, err := clientset.AppsV1().Deployments(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for _, d := range list.Items {
+			found = append(found, d.Namespace+"/"+d.Name)
+		}
+		return nil
+	})
+	sort.Strings(found)
+	return found, skipped, err
+}
+
+func TestListInScope_PartialAccess(t *testing.T) {
+	originalAll := allNamespaces
+	defer func() { allNamespaces = originalAll }()
+	allNamespaces = true
+
+	found, skipped, err := listTestDeployments(newPartialAccessClientset([]string{"dev", "prod", "staging"}, "prod"))
+	if err != nil {
+		t.Fatalf("expected partial success, got %v", err)
+	}
+	if strings.Join(found, ",") != "dev/web,staging/web" {
+		t.Errorf("found = %v, want dev/web and staging/web", found)
+	}
+	if len(skipped) != 1 || skipped[0].Namespace != "prod" {
+		t.Fatalf("skipped = %v, want prod", skipped)
+	}
+
+	var footer bytes.Buffer
+	printSkippedNamespaces(&footer, skipped)
+	if !strings.Contains(footer.String(), "prod: ") {
+		t.Errorf("footer does not mention prod: %q", footer.String())
+	}
+}
+
+func TestListInScope_AllDenied(t *testing.T) {
+	originalAll := allNamespaces
+	defer func() { allNamespaces = originalAll }()
+	allNamespaces = true
+
+	_, _, err := listTestDeployments(newPartialAccessClientset([]string{"dev", "prod"}, "dev", "prod"))
+	if err == nil {
+		t.Fatal("expected error when every namespace is denied")
+	}
+	if exitCodeFor(err) != exitForbidden {
+		t.Errorf("exit code = %d, want %d", exitCodeFor(err), exitForbidden)
+	}
+}
+
+func TestListInScope_SingleNamespace(t *testing.T) {
+	originalAll, originalNamespace := allNamespaces, namespace
+	defer func() { allNamespaces, namespace = originalAll, originalNamespace }()
+	allNamespaces, namespace = false, "dev"
+
+	found, skipped, err := listTestDeployments(newPartialAccessClientset([]string{"dev", "prod"}))
+	if err != nil || len(skipped) != 0 {
+		t.Fatalf("unexpected result: skipped=%v err=%v", skipped, err)
+	}
+	if strings.Join(found, ",") != "dev/web" {
+		t.Errorf("found = %v, want dev/web", found)
+	}
+}
@@ -1,6 +1,25 @@
 package cmd
 
-import "testing"
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/list"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/testutil"
+)
 
 func TestGetKubeClient_InvalidPath(t *testing.T) {
 	originalKubeconfig := kubeconfig
@@ -13,3 +32,261 @@ func TestGetKubeClient_InvalidPath(t *testing.T) {
 		t.Error("expected error for invalid kubeconfig path")
 	}
 }
+
+func TestGetKubeConfig_MergesKubeconfigList(t *testing.T) {
+	originalKubeconfig, originalContext := kubeconfig, kubeContext
+	defer func() { kubeconfig, kubeContext = originalKubeconfig, originalContext }()
+	kubeconfig, kubeContext = "", ""
+
+	// a only selects the context; the context, cluster and user live in b.
+	a, b := testutil.WriteSplitKubeconfig(t)
+	t.Setenv("KUBECONFIG", a+string(os.PathListSeparator)+b)
+
+	config, err := getKubeConfig()
+	if err != nil {
+		t.Fatalf("getKubeConfig returned error: %v", err)
+	}
+	if config.Host != testutil.SplitKubeconfigServer {
+		t.Errorf("Host = %q, want the server defined in the second file", config.Host)
+	}
+	if config.BearerToken != testutil.SplitKubeconfigToken {
+		t.Errorf("BearerToken = %q, want the token defined in the second file", config.BearerToken)
+	}
+
+	kubeContext = "missing"
+	if _, err := getKubeConfig(); err == nil {
+		t.Error("expected error for an unknown --context")
+	}
+}
+
+func TestGetPodActivityTime(t *testing.T) {
+	created := time.Now().Add(-2 * time.Hour)
+	restarted := time.Now().Add(-5 * time.Minute)
+
+	pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+	if got := getPodActivityTime(pod); !got.Equal(created) {
+		t.Errorf("getPodActivityTime() = %v, want creation time %v", got, created)
+	}
+
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		RestartCount: 1,
+		State: corev1.ContainerState{
+			Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(restarted)},
+		},
+	}}
+	if got := getPodActivityTime(pod); !got.Equal(restarted) {
+		t.Errorf("getPodActivityTime() = %v, want restart time %v", got, restarted)
+	}
+}
+
+func TestFilterPodsSince(t *testing.T) {
+	newPod := func(name string, age time.Duration) corev1.Pod {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if age > 0 {
+			pod.CreationTimestamp = metav1.NewTime(time.Now().Add(-age))
+		}
+		return pod
+	}
+	pods := []corev1.Pod{
+		newPod("recent", 2*time.Minute),
+		newPod("old", 3*time.Hour),
+		newPod("unknown", 0),
+	}
+
+	got := filterPodsSince(pods, 10*time.Minute)
+	if len(got) != 1 || got[0].Name != "recent" {
+		t.Errorf("filterPodsSince() = %v, want only 'recent'", got)
+	}
+}
+
+func TestListPods_NegativeSince(t *testing.T) {
+	if err := listPods(-time.Minute); err == nil {
+		t.Error("expected error for negative --since")
+	}
+}
+
+func TestParseDeploymentStrategy(t *testing.T) {
+	tests := []struct {
+		name           string
+		strategy       string
+		maxSurge       string
+		maxUnavailable string
+		wantType       appsv1.DeploymentStrategyType
+		wantSurge      string
+		wantUnavail    string
+		wantErr        bool
+	}{
+		{name: "defaults"},
+		{name: "recreate", strategy: "Recreate", wantType: appsv1.RecreateDeploymentStrategyType},
+		{name: "case insensitive", strategy: "rollingupdate", wantType: appsv1.RollingUpdateDeploymentStrategyType},
+		{name: "zero surge", maxSurge: "0", maxUnavailable: "1", wantType: appsv1.RollingUpdateDeploymentStrategyType, wantSurge: "0", wantUnavail: "1"},
+		{name: "percent", strategy: "RollingUpdate", maxSurge: "50%", wantType: appsv1.RollingUpdateDeploymentStrategyType, wantSurge: "50%"},
+		{name: "unknown strategy", strategy: "BlueGreen", wantErr: true},
+		{name: "recreate with surge", strategy: "Recreate", maxSurge: "1", wantErr: true},
+		{name: "both zero", maxSurge: "0%", maxUnavailable: "0", wantErr: true},
+		{name: "negative", maxSurge: "-1", wantErr: true},
+		{name: "percent too large", maxUnavailable: "150%", wantErr: true},
+		{name: "not a number", maxSurge: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDeploymentStrategy(tt.strategy, tt.maxSurge, tt.maxUnavailable)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Type != tt.wantType {
+				t.Errorf("Type = %q, want %q", got.Type, tt.wantType)
+			}
+			var surge, unavailable string
+			if got.RollingUpdate != nil {
+				if got.RollingUpdate.MaxSurge != nil {
+					surge = got.RollingUpdate.MaxSurge.String()
+				}
+				if got.RollingUpdate.MaxUnavailable != nil {
+					unavailable = got.RollingUpdate.MaxUnavailable.String()
+				}
+			}
+			if surge != tt.wantSurge || unavailable != tt.wantUnavail {
+				t.Errorf("MaxSurge/MaxUnavailable = %q/%q, want %q/%q", surge, unavailable, tt.wantSurge, tt.wantUnavail)
+			}
+		})
+	}
+}
+
+func TestBuildDeployment_Strategy(t *testing.T) {
+	strategy, err := parseDeploymentStrategy("Recreate", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dep := buildDeployment("web", "nginx", deploymentOptions{Replicas: 3, Strategy: strategy})
+	if *dep.Spec.Replicas != 3 {
+		t.Errorf("Replicas = %d, want 3", *dep.Spec.Replicas)
+	}
+	if dep.Spec.Strategy.Type != appsv1.RecreateDeploymentStrategyType {
+		t.Errorf("Strategy.Type = %q, want Recreate", dep.Spec.Strategy.Type)
+	}
+}
+
+func TestEnsureNamespace(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "existing"}})
+
+	if err := ensureNamespace(ctx, clientset, "existing", false); err != nil {
+		t.Errorf("expected existing namespace to pass, got %v", err)
+	}
+
+	err := ensureNamespace(ctx, clientset, "missing", false)
+	if err == nil || !strings.Contains(err.Error(), "create namespace missing") {
+		t.Errorf("expected friendly missing namespace error, got %v", err)
+	}
+
+	if err := ensureNamespace(ctx, clientset, "missing", true); err != nil {
+		t.Fatalf("expected namespace to be created, got %v", err)
+	}
+	if _, err := clientset.CoreV1().Namespaces().Get(ctx, "missing", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected namespace 'missing' to exist, got %v", err)
+	}
+}
+
+func TestEnsureNamespace_ForbiddenSkipsCheck(t *testing.T) {
+	clientset := fake.NewClientset()
+	clientset.PrependReactor("get", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, "team-a", nil)
+	})
+	if err := ensureNamespace(context.Background(), clientset, "team-a", false); err != nil {
+		t.Errorf("expected forbidden namespace read to be ignored, got %v", err)
+	}
+}
+
+func TestEnsureNamespace_SetsFieldManager(t *testing.T) {
+	originalFieldManager := fieldManager
+	defer func() { fieldManager = originalFieldManager }()
+	fieldManager = "ci-bot"
+
+	clientset := fake.NewClientset()
+	var got string
+	clientset.PrependReactor("create", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		got = action.(k8stesting.CreateActionImpl).GetCreateOptions().FieldManager
+		return false, nil, nil
+	})
+	if err := ensureNamespace(context.Background(), clientset, "team-a", true); err != nil {
+		t.Fatalf("ensureNamespace returned error: %v", err)
+	}
+	if got != "ci-bot" {
+		t.Errorf("FieldManager = %q, want %q", got, "ci-bot")
+	}
+}
+
+func TestCreateOnce(t *testing.T) {
+	originalIfNotExists := createIfNotExists
+	defer func() { createIfNotExists = originalIfNotExists }()
+
+	ctx := context.Background()
+	clientset := fake.NewClientset(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}})
+	deployments := clientset.AppsV1().Deployments("default")
+	createWeb := func(name string) (bool, string, error) {
+		var out bytes.Buffer
+		created, err := createOnce(&out, "Deployment '"+name+"' in namespace 'default'", func() error {
+			_, err := deployments.Get(ctx, name, metav1.GetOptions{})
+			return err
+		}, func() error {
+			_, err := deployments.Create(ctx, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name}}, metav1.CreateOptions{})
+			return err
+		})
+		return created, out.String(), err
+	}
+
+	// Without the flag an existing object is still an error.
+	createIfNotExists = false
+	if _, _, err := createWeb("web"); !apierrors.IsAlreadyExists(err) {
+		t.Errorf("expected AlreadyExists without --if-not-exists, got %v", err)
+	}
+
+	createIfNotExists = true
+	created, out, err := createWeb("web")
+	if err != nil || created {
+		t.Errorf("createOnce = %v, %v, want a skip for the existing deployment", created, err)
+	}
+	if out != "Deployment 'web' in namespace 'default' already exists, skipping\n" {
+		t.Errorf("output = %q", out)
+	}
+	if created, _, err := createWeb("api"); err != nil || !created {
+		t.Errorf("createOnce = %v, %v, want a missing deployment to be created", created, err)
+	}
+
+	// An object created by someone else after the Get is skipped as well.
+	clientset.PrependReactor("get", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewNotFound(appsv1.Resource("deployments"), "web")
+	})
+	if created, out, err := createWeb("web"); err != nil || created || !strings.Contains(out, "skipping") {
+		t.Errorf("createOnce = %v, %v (%q), want a skip when create reports AlreadyExists", created, err, out)
+	}
+}
+
+func TestDefaultPodColumns(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: true, RestartCount: 2}},
+		},
+	}
+	var buf bytes.Buffer
+	if err := list.PrintTable(&buf, podsResource, []runtime.Object{pod}, false); err != nil {
+		t.Fatalf("PrintTable returned error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if got := strings.Join(strings.Fields(lines[0]), " "); got != "NAME READY STATUS RESTARTS AGE" {
+		t.Errorf("header = %q", got)
+	}
+	if got := strings.Join(strings.Fields(lines[1]), " "); got != "web-1 1/1 Running 2 unknown" {
+		t.Errorf("row = %q", got)
+	}
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// logsResyncInterval is how often a followed deployment's pod set is re-listed.
+const logsResyncInterval = 5 * time.Second
+
+var logColors = []string{"\033[31m", "\033[32m", "\033[33m", "\033[34m", "\033[35m", "\033[36m"}
+
+const logColorReset = "\033[0m"
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Print logs of Kubernetes resources",
+	Long:  "Print and stream logs from all pods of Kubernetes workloads like deployments",
+}
+
+var logsDeploymentCmd = &cobra.Command{
+	Use:     "deployment [name]",
+	Short:   "Print logs from all pods of a deployment",
+	Aliases: []string{"deploy"},
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		follow, _ := cmd.Flags().GetBool("follow")
+		container, _ := cmd.Flags().GetString("container")
+		tail, _ := cmd.Flags().GetInt64("tail")
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if err := logsDeployment(ctx, name, logsOptions{Follow: follow, Container: container, Tail: tail}); err != nil {
+			exitWithError("Failed to get deployment logs", err)
+		}
+	},
+}
+
+type logsOptions struct {
+	Follow    bool
+	Container string
+	Tail      int64
+}
+
+func logsDeployment(ctx context.Context, name string, opts logsOptions) error {
+	log.Info().Str("name", name).Str("namespace", namespace).Bool("follow", opts.Follow).Msg("Streaming deployment logs")
+
+	clientset, err := getKubeClient()
+	if err != nil {
+		return err
+	}
+	printer := &logPrinter{out: os.Stdout, color: term.IsTerminal(int(os.Stdout.Fd()))}
+	return streamDeploymentLogs(ctx, clientset, namespace, name, opts, printer)
+}
+
+// streamDeploymentLogs prints the logs of every pod matching the deployment's selector.
+// When following, the pod set is re-listed periodically so pods started during the
+// stream are picked up and streams of removed pods are stopped.
+func streamDeploymentLogs(ctx context.Context, clientset kubernetes.Interface, ns, name string, opts logsOptions, printer *logPrinter) error {
+	deployment, err := clientset.AppsV1().Deployments(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get deployment: %w", err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return fmt.Errorf("invalid deployment selector: %w", err)
+	}
+
+	s := &logStreamer{
+		clientset: clientset,
+		namespace: ns,
+		selector:  selector,
+		opts:      opts,
+		printer:   printer,
+		active:    map[string]context.CancelFunc{},
+	}
+	defer s.wg.Wait()
+
+	if err := s.reconcile(ctx, true); err != nil {
+		return err
+	}
+	if !opts.Follow {
+		return nil
+	}
+
+	ticker := time.NewTicker(logsResyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.reconcile(ctx, false); err != nil {
+				log.Warn().Err(err).Msg("Failed to refresh deployment pods")
+			}
+		}
+	}
+}
+
+// logStreamer tracks one log stream per container instance.
+type logStreamer struct {
+	clientset kubernetes.Interface
+	namespace string
+	selector  labels.Selector
+	opts      logsOptions
+	printer   *logPrinter
+
+	mu     sync.Mutex
+	active map[string]context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func (s *logStreamer) reconcile(ctx context.Context, initial bool) error {
+	pods, err := s.clientset.CoreV1().Pods(s.namespace).List(ctx, metav1.ListOptions{LabelSelector: s.selector.String()})
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+	if initial && len(pods.Items) == 0 {
+		fmt.Fprintf(os.Stderr, "No pods found for deployment in namespace '%s'\n", s.namespace)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := map[string]bool{}
+	for _, pod := range pods.Items {
+		multi := len(pod.Spec.Containers) > 1
+		for _, container := range pod.Spec.Containers {
+			if s.opts.Container != "" && container.Name != s.opts.Container {
+				continue
+			}
+			status, started := getContainerLogStatus(pod, container.Name)
+			if !started {
+				continue
+			}
+			// A restart produces a new key so the new container instance is streamed from its start.
+			key := fmt.Sprintf("%s/%s/%d", pod.UID, container.Name, status.RestartCount)
+			seen[key] = true
+			if _, ok := s.active[key]; ok {
+				continue
+			}
+
+			prefix := pod.Name
+			if multi {
+				prefix = pod.Name + "/" + container.Name
+			}
+			logOpts := &corev1.PodLogOptions{Container: container.Name, Follow: s.opts.Follow}
+			if initial && s.opts.Tail >= 0 {
+				logOpts.TailLines = &s.opts.Tail
+			}
+
+			streamCtx, cancel := context.WithCancel(ctx)
+			s.active[key] = cancel
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.stream(streamCtx, pod.Name, prefix, logOpts)
+			}()
+		}
+	}
+
+	for key, cancel := range s.active {
+		if !seen[key] {
+			cancel()
+			delete(s.active, key)
+		}
+	}
+	return nil
+}
+
+func (s *logStreamer) stream(ctx context.Context, podName, prefix string, opts *corev1.PodLogOptions) {
+	stream, err := s.clientset.CoreV1().Pods(s.namespace).GetLogs(podName, opts).Stream(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Warn().Err(err).Str("pod", podName).Str("container", opts.Container).Msg("Failed to stream logs")
+		}
+		return
+	}
+	defer stream.Close()
+
+	reader := bufio.NewReader(stream)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			s.printer.printLine(prefix, line)
+		}
+		if err != nil {
+			if err != io.EOF && ctx.Err() == nil {
+				log.Warn().Err(err).Str("pod", podName).Str("container", opts.Container).Msg("Log stream interrupted")
+			}
+			return
+		}
+	}
+}
+
+// getContainerLogStatus reports whether the container has started at least once, so it has logs to read.
+func getContainerLogStatus(pod corev1.Pod, container string) (corev1.ContainerStatus, bool) {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != container {
+			continue
+		}
+		started := status.State.Running != nil || status.State.Terminated != nil || status.LastTerminationState.Terminated != nil
+		return status, started
+	}
+	return corev1.ContainerStatus{}, false
+}
+
+// logPrinter serialises lines from concurrent streams, prefixing each with its source.
+// On a terminal every source gets its own color.
+type logPrinter struct {
+	out   io.Writer
+	color bool
+
+	mu     sync.Mutex
+	colors map[string]string
+}
+
+func (p *logPrinter) printLine(prefix, line string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(line) == 0 || line[len(line)-1] != '\n' {
+		line += "\n"
+	}
+	if !p.color {
+		fmt.Fprintf(p.out, "[%s] %s", prefix, line)
+		return
+	}
+	if p.colors == nil {
+		p.colors = map[string]string{}
+	}
+	color, ok := p.colors[prefix]
+	if !ok {
+		color = logColors[len(p.colors)%len(logColors)]
+		p.colors[prefix] = color
+	}
+	fmt.Fprintf(p.out, "%s[%s]%s %s", color, prefix, logColorReset, line)
+}
+
+func init() {
+	rootCmd.AddCommand(logsCmd)
+	logsCmd.AddCommand(logsDeploymentCmd)
+
+	logsDeploymentCmd.Flags().BoolP("follow", "f", false, "Stream logs as they are written")
+	logsDeploymentCmd.Flags().StringP("container", "c", "", "Only print logs of this container (default: all containers)")
+	logsDeploymentCmd.Flags().Int64("tail", -1, "Number of recent lines to show per container (-1 shows all)")
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newLogsTestPod(name string, labels map[string]string, containers ...string) *corev1.Pod {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels, UID: types.UID(name)}}
+	for _, c := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: c})
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+			Name:  c,
+			State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		})
+	}
+	return pod
+}
+
+func TestStreamDeploymentLogs(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		},
+	}
+	pending := newLogsTestPod("web-pending", map[string]string{"app": "web"}, "nginx")
+	pending.Status.ContainerStatuses[0].State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}}
+
+	clientset := fake.NewClientset(
+		deployment,
+		newLogsTestPod("web-1", map[string]string{"app": "web"}, "nginx"),
+		newLogsTestPod("web-2", map[string]string{"app": "web"}, "nginx", "sidecar"),
+		newLogsTestPod("other", map[string]string{"app": "other"}, "nginx"),
+		pending,
+	)
+
+	var buf bytes.Buffer
+	printer := &logPrinter{out: &buf}
+	if err := streamDeploymentLogs(context.Background(), clientset, "default", "web", logsOptions{Tail: -1}, printer); err != nil {
+		t.Fatalf("streamDeploymentLogs returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	sort.Strings(lines)
+	want := []string{
+		"[web-1] fake logs",
+		"[web-2/nginx] fake logs",
+		"[web-2/sidecar] fake logs",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got lines:\n%s\nwant:\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestStreamDeploymentLogs_NotFound(t *testing.T) {
+	err := streamDeploymentLogs(context.Background(), fake.NewClientset(), "default", "missing", logsOptions{}, &logPrinter{out: &bytes.Buffer{}})
+	if err == nil {
+		t.Error("expected error for missing deployment")
+	}
+}
+
+func TestLogPrinter_Colors(t *testing.T) {
+	var buf bytes.Buffer
+	printer := &logPrinter{out: &buf, color: true}
+	printer.printLine("web-1", "first")
+	printer.printLine("web-2", "second\n")
+	printer.printLine("web-1", "third\n")
+
+	want := logColors[0] + "[web-1]" + logColorReset + " first\n" +
+		logColors[1] + "[web-2]" + logColorReset + " second\n" +
+		logColors[0] + "[web-1]" + logColorReset + " third\n"
+	if buf.String() != want {
+		t.Errorf("printLine output = %q, want %q", buf.String(), want)
+	}
+}
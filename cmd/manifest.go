@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/yaml"
+)
+
+// readManifests decodes all objects from a manifest file. A path of "-" reads from stdin.
+func readManifests(path string) ([]*unstructured.Unstructured, error) {
+	if path == "-" {
+		return decodeManifests(os.Stdin)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open manifest: %w", err)
+	}
+	defer f.Close()
+	return decodeManifests(f)
+}
+
+// decodeManifests decodes a multi-document YAML or JSON stream into objects.
+// Empty documents are skipped and List kinds are expanded into their items.
+func decodeManifests(r io.Reader) ([]*unstructured.Unstructured, error) {
+	var objs []*unstructured.Unstructured
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(r))
+	for doc := 1; ; doc++ {
+		data, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read document %d: %w", doc, err)
+		}
+		jsonData, err := yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse document %d: %w", doc, err)
+		}
+		if len(bytes.TrimSpace(jsonData)) == 0 || bytes.Equal(bytes.TrimSpace(jsonData), []byte("null")) {
+			continue
+		}
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(jsonData); err != nil {
+			return nil, fmt.Errorf("failed to decode document %d: %w", doc, err)
+		}
+		if !obj.IsList() {
+			objs = append(objs, obj)
+			continue
+		}
+		err = obj.EachListItem(func(item runtime.Object) error {
+			objs = append(objs, item.(*unstructured.Unstructured))
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand list in document %d: %w", doc, err)
+		}
+	}
+	return objs, nil
+}
+
+func deleteFromFile(path string, ignoreNotFound bool) error {
+	log.Info().Str("file", path).Str("namespace", namespace).Msg("Deleting resources from file")
+
+	objs, err := readManifests(path)
+	if err != nil {
+		return err
+	}
+	if len(objs) == 0 {
+		fmt.Println("No objects found in manifest")
+		return nil
+	}
+
+	config, err := getKubeConfig()
+	if err != nil {
+		return err
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+
+	var errs []error
+	for _, obj := range objs {
+		if err := deleteObject(context.Background(), dynamicClient, mapper, obj, ignoreNotFound); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deleteObject deletes a single manifest object, resolving its resource through the mapper.
+// Objects without a namespace fall back to the --namespace flag.
+func deleteObject(ctx context.Context, client dynamic.Interface, mapper meta.RESTMapper, obj *unstructured.Unstructured, ignoreNotFound bool) error {
+	gvk := obj.GroupVersionKind()
+	name := obj.GetName()
+	if name == "" {
+		return fmt.Errorf("%s object has no name", gvk.Kind)
+	}
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return fmt.Errorf("failed to resolve resource for %s '%s': %w", gvk.Kind, name, err)
+	}
+
+	var resource dynamic.ResourceInterface = client.Resource(mapping.Resource)
+	ns := ""
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		ns = obj.GetNamespace()
+		if ns == "" {
+			ns = namespace
+		}
+		resource = client.Resource(mapping.Resource).Namespace(ns)
+	}
+
+	err = resource.Delete(ctx, name, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) && ignoreNotFound {
+		log.Warn().Str("kind", gvk.Kind).Str("name", name).Str("namespace", ns).Msg("Object not found, treating as already deleted")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete %s '%s': %w", gvk.Kind, name, err)
+	}
+
+	if ns == "" {
+		fmt.Printf("%s '%s' deleted successfully\n", gvk.Kind, name)
+	} else {
+		fmt.Printf("%s '%s' deleted successfully from namespace '%s'\n", gvk.Kind, name, ns)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const testManifest = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: apps
+---
+# comment-only document
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Namespace
+  metadata:
+    name: apps
+- apiVersion: v1
+  kind: Pod
+  metadata:
+    name: debug
+`
+
+func TestDecodeManifests(t *testing.T) {
+	objs, err := decodeManifests(strings.NewReader(testManifest))
+	if err != nil {
+		t.Fatalf("decodeManifests returned error: %v", err)
+	}
+	want := []string{"Deployment/web", "Namespace/apps", "Pod/debug"}
+	if len(objs) != len(want) {
+		t.Fatalf("got %d objects, want %d", len(objs), len(want))
+	}
+	for i, obj := range objs {
+		if got := obj.GetKind() + "/" + obj.GetName(); got != want[i] {
+			t.Errorf("object %d = %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+func TestDecodeManifests_MissingKind(t *testing.T) {
+	_, err := decodeManifests(strings.NewReader("apiVersion: v1\nmetadata:\n  name: x\n"))
+	if err == nil {
+		t.Error("expected error for document without kind")
+	}
+}
+
+func newTestRESTMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+	return mapper
+}
+
+func toUnstructured(t *testing.T, obj runtime.Object) *unstructured.Unstructured {
+	t.Helper()
+	data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &unstructured.Unstructured{Object: data}
+}
+
+func TestDeleteObject(t *testing.T) {
+	originalNamespace := namespace
+	defer func() { namespace = originalNamespace }()
+	namespace = "default"
+
+	existing := &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+	}
+	client := dynamicfake.NewSimpleDynamicClient(scheme.Scheme, existing)
+	mapper := newTestRESTMapper()
+	ctx := context.Background()
+
+	// Namespace comes from the --namespace flag when the manifest omits it.
+	obj := toUnstructured(t, &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Name: "web"},
+	})
+	if err := deleteObject(ctx, client, mapper, obj, true); err != nil {
+		t.Fatalf("deleteObject returned error: %v", err)
+	}
+
+	// Deleting again is only an error when not-found objects are not ignored.
+	if err := deleteObject(ctx, client, mapper, obj, true); err != nil {
+		t.Errorf("expected missing object to be ignored, got %v", err)
+	}
+	if err := deleteObject(ctx, client, mapper, obj, false); err == nil {
+		t.Error("expected error for missing object with ignore-not-found=false")
+	}
+}
+
+func TestDeleteObject_UnknownKind(t *testing.T) {
+	client := dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("example.com/v1")
+	obj.SetKind("Widget")
+	obj.SetName("w")
+	if err := deleteObject(context.Background(), client, newTestRESTMapper(), obj, true); err == nil {
+		t.Error("expected error for unknown kind")
+	}
+}
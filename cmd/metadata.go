@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// metadataOptions holds extra labels and annotations for created objects, e.g. to tag
+// them for cost allocation or ownership.
+type metadataOptions struct {
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// apply merges the labels and annotations into meta, overriding keys it already has.
+func (o metadataOptions) apply(meta *metav1.ObjectMeta) {
+	meta.Labels = mergeStringMaps(meta.Labels, o.Labels)
+	meta.Annotations = mergeStringMaps(meta.Annotations, o.Annotations)
+}
+
+func mergeStringMaps(dst, src map[string]string) map[string]string {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]string, len(src))
+	}
+	maps.Copy(dst, src)
+	return dst
+}
+
+// addMetadataFlags registers --label and --annotation as persistent flags of cmd, so
+// every create subcommand accepts them.
+func addMetadataFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringArray("label", nil, "Add a label to the created object, as key=value (repeatable)")
+	cmd.PersistentFlags().StringArray("annotation", nil, "Add an annotation to the created object, as key=value (repeatable)")
+}
+
+// metadataFromFlags parses the flags registered by addMetadataFlags.
+func metadataFromFlags(cmd *cobra.Command) (metadataOptions, error) {
+	labels, _ := cmd.Flags().GetStringArray("label")
+	annotations, _ := cmd.Flags().GetStringArray("annotation")
+	return parseMetadataOptions(labels, annotations)
+}
+
+func parseMetadataOptions(labels, annotations []string) (metadataOptions, error) {
+	var opts metadataOptions
+	for _, s := range labels {
+		key, value, err := parseMetadataPair("--label", s)
+		if err != nil {
+			return opts, err
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return opts, fmt.Errorf("invalid --label value %q: %s", value, strings.Join(errs, "; "))
+		}
+		if opts.Labels == nil {
+			opts.Labels = map[string]string{}
+		}
+		opts.Labels[key] = value
+	}
+	for _, s := range annotations {
+		key, value, err := parseMetadataPair("--annotation", s)
+		if err != nil {
+			return opts, err
+		}
+		if opts.Annotations == nil {
+			opts.Annotations = map[string]string{}
+		}
+		opts.Annotations[key] = value
+	}
+	return opts, nil
+}
+
+// parseMetadataPair splits key=value and checks that key is a qualified name, optionally
+// prefixed with a DNS subdomain such as example.com/team.
+func parseMetadataPair(flag, s string) (string, string, error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("invalid %s %q: must be key=value", flag, s)
+	}
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return "", "", fmt.Errorf("invalid %s key %q: %s", flag, key, strings.Join(errs, "; "))
+	}
+	return key, value, nil
+}
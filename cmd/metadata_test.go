@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseMetadataOptions(t *testing.T) {
+	opts, err := parseMetadataOptions(
+		[]string{"team=payments", "example.com/cost-center=cc-42", "team=billing"},
+		[]string{"owner=jane@example.com", "example.com/note=created by setup.sh"},
+	)
+	if err != nil {
+		t.Fatalf("parseMetadataOptions returned error: %v", err)
+	}
+	if len(opts.Labels) != 2 || opts.Labels["team"] != "billing" || opts.Labels["example.com/cost-center"] != "cc-42" {
+		t.Errorf("labels = %v, want team=billing (last value wins) and the cost center", opts.Labels)
+	}
+	if opts.Annotations["owner"] != "jane@example.com" || opts.Annotations["example.com/note"] != "created by setup.sh" {
+		t.Errorf("annotations = %v", opts.Annotations)
+	}
+
+	for _, tt := range []struct{ labels, annotations []string }{
+		{labels: []string{"team"}},
+		{labels: []string{"=payments"}},
+		{labels: []string{"bad key=x"}},
+		{labels: []string{"team=has spaces"}},
+		{annotations: []string{"owner"}},
+		{annotations: []string{"-owner=jane"}},
+	} {
+		if _, err := parseMetadataOptions(tt.labels, tt.annotations); err == nil {
+			t.Errorf("expected error for --label %v --annotation %v", tt.labels, tt.annotations)
+		}
+	}
+}
+
+func TestMetadataOptionsApply(t *testing.T) {
+	meta := metav1.ObjectMeta{Labels: map[string]string{"app": "web"}}
+	metadataOptions{}.apply(&meta)
+	if len(meta.Labels) != 1 || meta.Annotations != nil {
+		t.Errorf("empty options changed metadata: %+v", meta)
+	}
+
+	metadataOptions{Labels: map[string]string{"team": "payments"}, Annotations: map[string]string{"owner": "jane"}}.apply(&meta)
+	if meta.Labels["app"] != "web" || meta.Labels["team"] != "payments" || meta.Annotations["owner"] != "jane" {
+		t.Errorf("metadata = %+v", meta)
+	}
+}
+
+func TestBuildDeploymentWithMetadata(t *testing.T) {
+	metadata := metadataOptions{Labels: map[string]string{"team": "payments"}, Annotations: map[string]string{"owner": "jane"}}
+	dep := buildDeployment("web", "nginx", deploymentOptions{Replicas: 1, Metadata: metadata})
+	if dep.Labels["team"] != "payments" || dep.Annotations["owner"] != "jane" {
+		t.Errorf("deployment metadata = %+v", dep.ObjectMeta)
+	}
+	template := dep.Spec.Template.ObjectMeta
+	if template.Labels["app"] != "web" || template.Labels["team"] != "payments" {
+		t.Errorf("template labels = %v, want app=web and team=payments", template.Labels)
+	}
+	if template.Annotations != nil {
+		t.Errorf("template annotations = %v, want none", template.Annotations)
+	}
+	if len(dep.Spec.Selector.MatchLabels) != 1 || dep.Spec.Selector.MatchLabels["app"] != "web" {
+		t.Errorf("selector = %v, want only app=web", dep.Spec.Selector.MatchLabels)
+	}
+
+	// Overriding the app label keeps the selector matching the pods.
+	dep = buildDeployment("web", "nginx", deploymentOptions{Replicas: 1, Metadata: metadataOptions{Labels: map[string]string{"app": "storefront"}}})
+	if dep.Spec.Template.Labels["app"] != "storefront" || dep.Spec.Selector.MatchLabels["app"] != "storefront" {
+		t.Errorf("template labels %v and selector %v should both use app=storefront", dep.Spec.Template.Labels, dep.Spec.Selector.MatchLabels)
+	}
+}
+
+func TestMetadataFlagsOnCreateCommands(t *testing.T) {
+	for _, cmd := range []*cobra.Command{createDeploymentCmd, createPodCmd, createNamespaceCmd, createSecretGenericCmd, createConfigMapCmd} {
+		for _, flag := range []string{"label", "annotation"} {
+			if cmd.InheritedFlags().Lookup(flag) == nil {
+				t.Errorf("%s is missing --%s", cmd.CommandPath(), flag)
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+var (
+	outputFormat string
+	quietOutput  bool
+)
+
+// errQuietWithOutput rejects --quiet combined with -o, which would be ambiguous.
+var errQuietWithOutput = errors.New("--quiet cannot be combined with --output")
+
+// newListPrinter parses the -o flag of the list commands and rejects combining it with --quiet.
+func newListPrinter() (outputPrinter, error) {
+	printer, err := newOutputPrinter(outputFormat)
+	if err != nil {
+		return nil, err
+	}
+	if quietOutput && printer != nil {
+		return nil, errQuietWithOutput
+	}
+	return printer, nil
+}
+
+// outputPrinter renders a list in its unstructured (JSON-like) form.
+type outputPrinter func(w io.Writer, data map[string]interface{}) error
+
+// newOutputPrinter parses an -o value. It returns a nil printer for the default table output,
+// so template errors are reported before any API call is made.
+func newOutputPrinter(format string) (outputPrinter, error) {
+	switch {
+	case format == "":
+		return nil, nil
+	case strings.HasPrefix(format, "go-template="):
+		tmpl, err := template.New("output").Parse(strings.TrimPrefix(format, "go-template="))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing go-template: %w", err)
+		}
+		return func(w io.Writer, data map[string]interface{}) error {
+			if err := tmpl.Execute(w, data); err != nil {
+				return fmt.Errorf("error executing go-template: %w", err)
+			}
+			return nil
+		}, nil
+	case strings.HasPrefix(format, "jsonpath="):
+		expr := strings.TrimPrefix(format, "jsonpath=")
+		if !strings.Contains(expr, "{") {
+			expr = "{" + expr + "}"
+		}
+		jp := jsonpath.New("output")
+		if err := jp.Parse(expr); err != nil {
+			return nil, fmt.Errorf("error parsing jsonpath %s: %w", expr, err)
+		}
+		return func(w io.Writer, data map[string]interface{}) error {
+			if err := jp.Execute(w, data); err != nil {
+				return fmt.Errorf("error executing jsonpath %s: %w", expr, err)
+			}
+			return nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output format %q (supported: go-template=..., jsonpath=...)", format)
+	}
+}
+
+// printList converts a typed list to unstructured form, filling in the apiVersion
+// and kind that the typed client leaves empty, and renders it with printer.
+func printList(w io.Writer, printer outputPrinter, list runtime.Object, gvk schema.GroupVersionKind) error {
+	data, err := runtime.DefaultUnstructuredConverter.ToUnstructured(list)
+	if err != nil {
+		return fmt.Errorf("failed to convert list: %w", err)
+	}
+	data["apiVersion"] = "v1"
+	data["kind"] = "List"
+	data["items"] = listItems(data, gvk)
+	return printer(w, data)
+}
+
+// listItems returns the items of an unstructured list, typed with gvk.
+func listItems(data map[string]interface{}, gvk schema.GroupVersionKind) []interface{} {
+	items, _ := data["items"].([]interface{})
+	for _, item := range items {
+		if obj, ok := item.(map[string]interface{}); ok {
+			obj["apiVersion"] = gvk.GroupVersion().String()
+			obj["kind"] = gvk.Kind
+		}
+	}
+	if items == nil {
+		items = []interface{}{}
+	}
+	return items
+}
+
+// printNames writes the name of each object on its own line, prefixed with
+// "namespace/" when withNamespace is set, for piping into other commands.
+func printNames(w io.Writer, objs []runtime.Object, withNamespace bool) error {
+	for _, obj := range objs {
+		accessor, err := meta.Accessor(obj)
+		if err != nil {
+			return err
+		}
+		if withNamespace {
+			fmt.Fprintf(w, "%s/%s\n", accessor.GetNamespace(), accessor.GetName())
+			continue
+		}
+		fmt.Fprintln(w, accessor.GetName())
+	}
+	return nil
+}
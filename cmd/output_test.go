@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func testDeploymentList() *appsv1.DeploymentList {
+	return &appsv1.DeploymentList{Items: []appsv1.Deployment{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"}},
+	}}
+}
+
+func TestNewOutputPrinter_Table(t *testing.T) {
+	printer, err := newOutputPrinter("")
+	if err != nil || printer != nil {
+		t.Errorf("newOutputPrinter(\"\") = %v, %v; want nil printer for table output", printer, err)
+	}
+}
+
+func TestNewOutputPrinter_Templates(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"go-template={{range .items}}{{.metadata.name}} {{end}}", "web api "},
+		{"go-template={{.kind}} {{(index .items 0).kind}}", "List Deployment"},
+		{"jsonpath={.items[*].metadata.name}", "web api"},
+		{"jsonpath=.items[0].apiVersion", "apps/v1"},
+	}
+	for _, tt := range tests {
+		printer, err := newOutputPrinter(tt.format)
+		if err != nil {
+			t.Fatalf("newOutputPrinter(%q) returned error: %v", tt.format, err)
+		}
+		var buf bytes.Buffer
+		if err := printList(&buf, printer, testDeploymentList(), appsv1.SchemeGroupVersion.WithKind("Deployment")); err != nil {
+			t.Fatalf("printList(%q) returned error: %v", tt.format, err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("printList(%q) = %q, want %q", tt.format, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestNewOutputPrinter_Errors(t *testing.T) {
+	for _, format := range []string{"go-template={{.items", "jsonpath={.items[", "yaml"} {
+		if _, err := newOutputPrinter(format); err == nil {
+			t.Errorf("newOutputPrinter(%q) expected error", format)
+		}
+	}
+
+	printer, err := newOutputPrinter("jsonpath={.missing.field}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = printList(&bytes.Buffer{}, printer, testDeploymentList(), appsv1.SchemeGroupVersion.WithKind("Deployment"))
+	if err == nil || !strings.Contains(err.Error(), "error executing jsonpath") {
+		t.Errorf("expected jsonpath execution error, got %v", err)
+	}
+}
+
+func TestPrintNames(t *testing.T) {
+	deployments := testDeploymentList()
+	deployments.Items[1].Namespace = "staging"
+	objs := []runtime.Object{&deployments.Items[0], &deployments.Items[1]}
+
+	var buf bytes.Buffer
+	if err := printNames(&buf, objs, false); err != nil {
+		t.Fatalf("printNames returned error: %v", err)
+	}
+	if got := buf.String(); got != "web\napi\n" {
+		t.Errorf("names = %q, want %q", got, "web\napi\n")
+	}
+
+	buf.Reset()
+	if err := printNames(&buf, objs, true); err != nil {
+		t.Fatalf("printNames returned error: %v", err)
+	}
+	if got := buf.String(); got != "default/web\nstaging/api\n" {
+		t.Errorf("names = %q, want %q", got, "default/web\nstaging/api\n")
+	}
+}
+
+func TestListDeployments_QuietWithOutput(t *testing.T) {
+	originalQuiet, originalFormat := quietOutput, outputFormat
+	defer func() { quietOutput, outputFormat = originalQuiet, originalFormat }()
+	quietOutput, outputFormat = true, "jsonpath={.items[*].metadata.name}"
+
+	if err := listDeployments(); err != errQuietWithOutput {
+		t.Errorf("expected errQuietWithOutput, got %v", err)
+	}
+}
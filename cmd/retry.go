@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+var apiRetries int
+
+const (
+	// maxRetryAfter caps how long a single Retry-After backoff may last.
+	maxRetryAfter = 30 * time.Second
+	// defaultRetryAfter is used when a Retry-After header cannot be parsed.
+	defaultRetryAfter = time.Second
+)
+
+// retryTransport retries requests answered with 429 Too Many Requests or 503 Service
+// Unavailable and a Retry-After header, waiting for the interval the API server asked for.
+//
+// client-go retries the same responses on its own, up to 10 times per request. To keep
+// --retries the only limit, the transport removes Retry-After from any 429 or 503 it
+// hands back, which client-go then reports as an error instead of retrying.
+type retryTransport struct {
+	next    http.RoundTripper
+	retries int
+	maxWait time.Duration
+}
+
+func newRetryTransport(next http.RoundTripper, retries int) http.RoundTripper {
+	return &retryTransport{next: next, retries: retries, maxWait: maxRetryAfter}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if err != nil || !isRetryableStatus(resp.StatusCode) {
+			return resp, err
+		}
+		retryAfter := resp.Header.Get("Retry-After")
+		// A PodDisruptionBudget blocks an eviction with 429; that is an answer, not throttling.
+		if retryAfter == "" || attempt > t.retries || isEviction(req) {
+			resp.Header.Del("Retry-After")
+			return resp, nil
+		}
+		// Requests whose body cannot be replayed are returned as-is.
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			resp.Header.Del("Retry-After")
+			return resp, nil
+		}
+
+		wait := parseRetryAfter(retryAfter, t.maxWait)
+		log.Warn().
+			Int("status", resp.StatusCode).
+			Str("method", req.Method).
+			Str("url", req.URL.Redacted()).
+			Int("attempt", attempt).
+			Int("max_retries", t.retries).
+			Dur("backoff", wait).
+			Msg("API server asked to retry, backing off")
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code == http.StatusServiceUnavailable
+}
+
+// isEviction reports whether req creates a pods/eviction subresource.
+func isEviction(req *http.Request) bool {
+	return req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/eviction")
+}
+
+// parseRetryAfter reads a Retry-After value given in seconds or as an HTTP date, capped at limit.
+func parseRetryAfter(value string, limit time.Duration) time.Duration {
+	wait := defaultRetryAfter
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		wait = max(time.Until(date), 0)
+	}
+	return min(wait, limit)
+}
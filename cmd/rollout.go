@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	watchtools "k8s.io/client-go/tools/watch"
+)
+
+// progressDeadlineExceededReason is set on the Progressing condition of a stalled rollout.
+const progressDeadlineExceededReason = "ProgressDeadlineExceeded"
+
+var rolloutCmd = &cobra.Command{
+	Use:   "rollout",
+	Short: "Manage the rollout of Kubernetes workloads",
+}
+
+var rolloutStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the status of a rollout",
+}
+
+var rolloutStatusDeploymentCmd = &cobra.Command{
+	Use:     "deployment [name]",
+	Short:   "Wait for a deployment rollout to finish and report its progress",
+	Aliases: []string{"deploy"},
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		watchRollout, _ := cmd.Flags().GetBool("watch")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		clientset, err := getKubeClient()
+		if err != nil {
+			exitWithError("Failed to create Kubernetes client", err)
+		}
+		if err := rolloutStatus(ctx, clientset, namespace, args[0], watchRollout, os.Stdout); err != nil {
+			exitWithError("Rollout did not complete", err)
+		}
+	},
+}
+
+// rolloutStatus prints the rollout progress of the deployment. With watch set it
+// follows the deployment until the rollout completes, fails or ctx is done.
+func rolloutStatus(ctx context.Context, clientset kubernetes.Interface, ns, name string, watchRollout bool, out io.Writer) error {
+	log.Info().Str("name", name).Str("namespace", ns).Bool("watch", watchRollout).Msg("Checking rollout status")
+
+	if !watchRollout {
+		deployment, err := clientset.AppsV1().Deployments(ns).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get deployment: %w", err)
+		}
+		msg, _, err := deploymentRolloutStatus(deployment)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, msg)
+		return nil
+	}
+
+	lastMsg := ""
+	_, err := watchDeployment(ctx, clientset, ns, name, func(event watch.Event) (bool, error) {
+		switch event.Type {
+		case watch.Deleted:
+			return false, apierrors.NewNotFound(appsv1.Resource("deployments"), name)
+		case watch.Added, watch.Modified:
+			deployment, ok := event.Object.(*appsv1.Deployment)
+			if !ok {
+				return false, fmt.Errorf("unexpected object type %T", event.Object)
+			}
+			msg, done, err := deploymentRolloutStatus(deployment)
+			if err != nil {
+				return false, err
+			}
+			if msg != lastMsg {
+				fmt.Fprintln(out, msg)
+				lastMsg = msg
+			}
+			return done, nil
+		}
+		return false, nil
+	})
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out waiting for deployment %q rollout: %w", name, context.DeadlineExceeded)
+	}
+	return err
+}
+
+// watchDeployment runs condition against the events of a single deployment until it
+// returns true or ctx is done. A deployment that is missing at the start fails with NotFound.
+func watchDeployment(ctx context.Context, clientset kubernetes.Interface, ns, name string, condition watchtools.ConditionFunc) (*watch.Event, error) {
+	deployments := clientset.AppsV1().Deployments(ns)
+	fieldSelector := fields.OneTermEqualSelector("metadata.name", name).String()
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = fieldSelector
+			return deployments.List(ctx, options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = fieldSelector
+			return deployments.Watch(ctx, options)
+		},
+	}
+	return watchtools.UntilWithSync(ctx, lw, &appsv1.Deployment{}, func(store cache.Store) (bool, error) {
+		if len(store.List()) == 0 {
+			return true, apierrors.NewNotFound(appsv1.Resource("deployments"), name)
+		}
+		return false, nil
+	}, condition)
+}
+
+// deploymentRolloutStatus evaluates a deployment the way kubectl rollout status does,
+// returning a progress message and whether the rollout has completed.
+func deploymentRolloutStatus(d *appsv1.Deployment) (string, bool, error) {
+	if d.Generation > d.Status.ObservedGeneration {
+		return "Waiting for deployment spec update to be observed...", false, nil
+	}
+	for _, cond := range d.Status.Conditions {
+		if cond.Type == appsv1.DeploymentProgressing && cond.Reason == progressDeadlineExceededReason {
+			return "", false, fmt.Errorf("deployment %q exceeded its progress deadline", d.Name)
+		}
+	}
+
+	desired := int32(1)
+	if d.Spec.Replicas != nil {
+		desired = *d.Spec.Replicas
+	}
+	status := d.Status
+	switch {
+	case status.UpdatedReplicas < desired:
+		return fmt.Sprintf("Waiting for deployment %q rollout to finish: %d of %d updated replicas...", d.Name, status.UpdatedReplicas, desired), false, nil
+	case status.Replicas > status.UpdatedReplicas:
+		return fmt.Sprintf("Waiting for deployment %q rollout to finish: %d old replicas are pending termination...", d.Name, status.Replicas-status.UpdatedReplicas), false, nil
+	case status.AvailableReplicas < status.UpdatedReplicas:
+		return fmt.Sprintf("Waiting for deployment %q rollout to finish: %d of %d updated replicas are available...", d.Name, status.AvailableReplicas, status.UpdatedReplicas), false, nil
+	}
+	return fmt.Sprintf("Deployment %q successfully rolled out", d.Name), true, nil
+}
+
+func init() {
+	rootCmd.AddCommand(rolloutCmd)
+	rolloutCmd.AddCommand(rolloutStatusCmd)
+	rolloutStatusCmd.AddCommand(rolloutStatusDeploymentCmd)
+
+	rolloutStatusDeploymentCmd.Flags().BoolP("watch", "w", true, "Wait until the rollout finishes instead of printing the current status once")
+	rolloutStatusDeploymentCmd.Flags().Duration("timeout", 0, "Give up waiting after this long, e.g. 5m (0 waits forever)")
+}
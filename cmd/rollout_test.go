@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newRolloutTestDeployment(replicas, updated, available int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Generation: 2},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			Replicas:           replicas,
+			UpdatedReplicas:    updated,
+			AvailableReplicas:  available,
+		},
+	}
+}
+
+func TestDeploymentRolloutStatus(t *testing.T) {
+	stale := newRolloutTestDeployment(3, 3, 3)
+	stale.Status.ObservedGeneration = 1
+	surging := newRolloutTestDeployment(3, 3, 3)
+	surging.Status.Replicas = 4
+	stalled := newRolloutTestDeployment(3, 1, 1)
+	stalled.Status.Conditions = []appsv1.DeploymentCondition{{
+		Type: appsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Reason: progressDeadlineExceededReason,
+	}}
+
+	tests := []struct {
+		name    string
+		d       *appsv1.Deployment
+		want    string
+		done    bool
+		wantErr bool
+	}{
+		{"spec not observed", stale, "spec update to be observed", false, false},
+		{"updating", newRolloutTestDeployment(3, 1, 1), "1 of 3 updated replicas", false, false},
+		{"old replicas terminating", surging, "1 old replicas are pending termination", false, false},
+		{"waiting for availability", newRolloutTestDeployment(3, 3, 2), "2 of 3 updated replicas are available", false, false},
+		{"complete", newRolloutTestDeployment(3, 3, 3), "successfully rolled out", true, false},
+		{"progress deadline exceeded", stalled, "", false, true},
+	}
+	for _, tt := range tests {
+		msg, done, err := deploymentRolloutStatus(tt.d)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !strings.Contains(msg, tt.want) || done != tt.done {
+			t.Errorf("%s: got (%q, %v), want message containing %q and done=%v", tt.name, msg, done, tt.want, tt.done)
+		}
+	}
+}
+
+// lockedBuffer lets the test read output written by the watching goroutine.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestRolloutStatus_WatchesUntilComplete(t *testing.T) {
+	clientset := fake.NewClientset(newRolloutTestDeployment(3, 1, 1))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var out lockedBuffer
+	done := make(chan error, 1)
+	go func() { done <- rolloutStatus(ctx, clientset, "default", "web", true, &out) }()
+
+	for !strings.Contains(out.String(), "1 of 3 updated replicas") {
+		select {
+		case err := <-done:
+			t.Fatalf("rolloutStatus returned early: %v", err)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	_, err := clientset.AppsV1().Deployments("default").UpdateStatus(ctx, newRolloutTestDeployment(3, 3, 3), metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("rolloutStatus returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), `Deployment "web" successfully rolled out`) {
+		t.Errorf("unexpected output:\n%s", out.String())
+	}
+}
+
+func TestRolloutStatus_Timeout(t *testing.T) {
+	clientset := fake.NewClientset(newRolloutTestDeployment(3, 1, 1))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := rolloutStatus(ctx, clientset, "default", "web", true, &bytes.Buffer{})
+	if exitCodeFor(err) != exitTimeout {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+}
+
+func TestRolloutStatus_NotFound(t *testing.T) {
+	err := rolloutStatus(context.Background(), fake.NewClientset(), "default", "missing", true, &bytes.Buffer{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
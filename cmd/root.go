@@ -24,6 +24,12 @@ This application is a tool to generate the needed files
 to quickly create a Cobra application.
 
 Version: ` + appVersion + "\n",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if errorFormat != "text" && errorFormat != "json" {
+			return fmt.Errorf("invalid --error-format %q: must be text or json", errorFormat)
+		}
+		return nil
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		level := parseLogLevel(logLevel)
 		configureLogger(level)
@@ -88,6 +94,10 @@ func configureLogger(level zerolog.Level) {
 
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
+		if errorFormat == "json" {
+			writeErrorJSON(os.Stderr, "Command failed", err)
+			os.Exit(exitCodeFor(err))
+		}
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -95,4 +105,5 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "Set log level: trace, debug, info, warn, error")
+	rootCmd.PersistentFlags().StringVar(&errorFormat, "error-format", "text", "Format of failure reports on stderr: text or json")
 }
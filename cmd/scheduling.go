@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// schedulingOptions steers created pods onto particular nodes.
+type schedulingOptions struct {
+	NodeSelector map[string]string
+	Tolerations  []corev1.Toleration
+}
+
+// apply sets the node selector and tolerations on spec.
+func (o schedulingOptions) apply(spec *corev1.PodSpec) {
+	spec.NodeSelector = o.NodeSelector
+	spec.Tolerations = o.Tolerations
+}
+
+// addSchedulingFlags registers --node-selector and --toleration on a create command.
+func addSchedulingFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("node-selector", nil, "Only schedule on nodes with this label, as key=value (repeatable)")
+	cmd.Flags().StringArray("toleration", nil, "Tolerate a node taint, as key=value:Effect or key:Effect (repeatable)")
+}
+
+// schedulingFromFlags parses the flags registered by addSchedulingFlags.
+func schedulingFromFlags(cmd *cobra.Command) (schedulingOptions, error) {
+	selectors, _ := cmd.Flags().GetStringArray("node-selector")
+	tolerations, _ := cmd.Flags().GetStringArray("toleration")
+	return parseSchedulingOptions(selectors, tolerations)
+}
+
+func parseSchedulingOptions(selectors, tolerations []string) (schedulingOptions, error) {
+	var opts schedulingOptions
+	for _, s := range selectors {
+		key, value, ok := strings.Cut(s, "=")
+		if !ok || key == "" {
+			return opts, fmt.Errorf("invalid --node-selector %q: must be key=value", s)
+		}
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return opts, fmt.Errorf("invalid --node-selector key %q: %s", key, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return opts, fmt.Errorf("invalid --node-selector value %q: %s", value, strings.Join(errs, "; "))
+		}
+		if opts.NodeSelector == nil {
+			opts.NodeSelector = map[string]string{}
+		}
+		opts.NodeSelector[key] = value
+	}
+	for _, t := range tolerations {
+		toleration, err := parseToleration(t)
+		if err != nil {
+			return opts, err
+		}
+		opts.Tolerations = append(opts.Tolerations, toleration)
+	}
+	return opts, nil
+}
+
+// parseToleration parses key=value:Effect into an Equal toleration and key:Effect into
+// an Exists toleration, matching the syntax of kubectl taint.
+func parseToleration(s string) (corev1.Toleration, error) {
+	spec, effect, ok := strings.Cut(s, ":")
+	if !ok || spec == "" {
+		return corev1.Toleration{}, fmt.Errorf("invalid --toleration %q: must be key=value:Effect or key:Effect", s)
+	}
+
+	toleration := corev1.Toleration{Operator: corev1.TolerationOpExists}
+	switch taintEffect := corev1.TaintEffect(effect); taintEffect {
+	case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		toleration.Effect = taintEffect
+	default:
+		return corev1.Toleration{}, fmt.Errorf("invalid --toleration %q: effect must be NoSchedule, PreferNoSchedule or NoExecute", s)
+	}
+
+	key, value, hasValue := strings.Cut(spec, "=")
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return corev1.Toleration{}, fmt.Errorf("invalid --toleration key %q: %s", key, strings.Join(errs, "; "))
+	}
+	toleration.Key = key
+	if hasValue {
+		toleration.Operator = corev1.TolerationOpEqual
+		toleration.Value = value
+	}
+	return toleration, nil
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestParseSchedulingOptions(t *testing.T) {
+	opts, err := parseSchedulingOptions(
+		[]string{"nvidia.com/gpu.present=true", "pool=spot"},
+		[]string{"nvidia.com/gpu=present:NoSchedule", "spot:PreferNoSchedule"},
+	)
+	if err != nil {
+		t.Fatalf("parseSchedulingOptions returned error: %v", err)
+	}
+	if opts.NodeSelector["nvidia.com/gpu.present"] != "true" || opts.NodeSelector["pool"] != "spot" {
+		t.Errorf("unexpected node selector: %v", opts.NodeSelector)
+	}
+	want := []corev1.Toleration{
+		{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpEqual, Value: "present", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "spot", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectPreferNoSchedule},
+	}
+	if len(opts.Tolerations) != len(want) {
+		t.Fatalf("got %d tolerations, want %d", len(opts.Tolerations), len(want))
+	}
+	for i := range want {
+		if opts.Tolerations[i] != want[i] {
+			t.Errorf("toleration %d = %+v, want %+v", i, opts.Tolerations[i], want[i])
+		}
+	}
+}
+
+func TestParseSchedulingOptions_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		selectors   []string
+		tolerations []string
+	}{
+		{"selector without value separator", []string{"pool"}, nil},
+		{"selector with invalid key", []string{"bad key=x"}, nil},
+		{"selector with invalid value", []string{"pool=not valid"}, nil},
+		{"toleration without effect", nil, []string{"pool=spot"}},
+		{"toleration with unknown effect", nil, []string{"pool=spot:NoRun"}},
+		{"toleration with lowercase effect", nil, []string{"pool=spot:noschedule"}},
+		{"toleration without key", nil, []string{":NoSchedule"}},
+	}
+	for _, tt := range tests {
+		if _, err := parseSchedulingOptions(tt.selectors, tt.tolerations); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestBuildWithScheduling(t *testing.T) {
+	scheduling := schedulingOptions{
+		NodeSelector: map[string]string{"pool": "gpu"},
+		Tolerations:  []corev1.Toleration{{Key: "gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+	}
+
+	deployment := buildDeployment("web", "nginx", deploymentOptions{Replicas: 1, Scheduling: scheduling})
+	if deployment.Spec.Template.Spec.NodeSelector["pool"] != "gpu" || len(deployment.Spec.Template.Spec.Tolerations) != 1 {
+		t.Errorf("deployment pod template not scheduled: %+v", deployment.Spec.Template.Spec)
+	}
+
+	pod := buildPod("web", "nginx", scheduling, containerOptions{})
+	if pod.Spec.NodeSelector["pool"] != "gpu" || len(pod.Spec.Tolerations) != 1 {
+		t.Errorf("pod not scheduled: %+v", pod.Spec)
+	}
+}
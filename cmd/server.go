@@ -2,20 +2,29 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
 
-	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
 	"github.com/yourusername/k8s-controller-tutorial/pkg/ctrl"
 	"github.com/yourusername/k8s-controller-tutorial/pkg/informer"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/tracing"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
 	ctrlruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	crmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 )
 
@@ -25,38 +34,96 @@ var serverInCluster bool
 var enableLeaderElection bool
 var leaderElectionNamespace string
 var metricsPort int
+var serverConfigFile string
+var serverEnableInformer bool
+var serverNamespace string
+var controllerDryRun bool
+var serverReadTimeout time.Duration
+var serverWriteTimeout time.Duration
+var serverIdleTimeout time.Duration
+var serverMaxRequestBodySize int
+var serverWaitForSync bool
+var serverFieldManager string
+var serverMetricsOnly bool
+var serverOtelEndpoint string
+var serverInformerStaleThreshold time.Duration
+var serverWebhookURL string
+var serverConsoleEvents bool
+var serverReconcileDebounce time.Duration
+var serverWatch []string
+var serverStatusConfigMap string
+var serverStatusNamespace string
+var serverInformerStartTimeout time.Duration
+var serverInformerRequired bool
+
+// watchableResources are the values accepted by the server's --watch flag.
+var watchableResources = []string{"deployments", "pods", "replicasets"}
 
 var serverCmd = &cobra.Command{
 	Use:   "server",
 	Short: "Start a FastHTTP server and deployment informer",
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return bindConfig(cmd, serverConfigFile)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		level := parseLogLevel(logLevel)
 		configureLogger(level)
 
-		// If kubeconfig is not provided via flag, check environment variable
-		if serverKubeconfig == "" {
-			serverKubeconfig = os.Getenv("KUBECONFIG")
+		shutdownTracing, err := tracing.Setup(context.Background(), serverOtelEndpoint, "k8s-controller", appVersion)
+		if err != nil {
+			exitWithError("Failed to set up tracing", err)
+		}
+		defer shutdownTracing(context.Background())
+		if serverOtelEndpoint != "" {
+			log.Info().Str("endpoint", serverOtelEndpoint).Msg("Exporting traces over OTLP")
 		}
 
-		clientset, err := getServerKubeClient(serverKubeconfig, serverInCluster)
+		config, err := getServerKubeConfig(serverKubeconfig, serverInCluster)
+		if err != nil {
+			exitWithError("Failed to build Kubernetes config", err)
+		}
+		clientset, err := kubernetes.NewForConfig(config)
 		if err != nil {
-			log.Error().Err(err).Msg("Failed to create Kubernetes client")
-			os.Exit(1)
+			exitWithError("Failed to create Kubernetes client", err)
 		}
+		watched, err := parseWatchedResources(serverWatch)
+		if err != nil {
+			exitWithError("Invalid --watch value", err)
+		}
+		watchDeployments := serverEnableInformer && watched["deployments"]
 
-		ctx := context.Background()
-		go informer.StartDeploymentInformer(ctx, clientset)
+		// SIGINT and SIGTERM stop the HTTP server, the manager and the informer, which
+		// logs a summary of what it observed before the process exits.
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
 
-		// Get the same config that we used for the clientset
-		var config *rest.Config
-		if serverInCluster {
-			config, err = rest.InClusterConfig()
+		informerDone := make(chan struct{})
+		if watchDeployments {
+			go func() {
+				defer close(informerDone)
+				err := startDeploymentInformer(ctx, informer.InformerConfig{
+					Clientset:      clientset,
+					Namespace:      serverNamespace,
+					StaleThreshold: serverInformerStaleThreshold,
+					WebhookURL:     serverWebhookURL,
+				})
+				if err != nil {
+					exitWithError("Deployment informer failed to start", err)
+				}
+			}()
 		} else {
-			config, err = clientcmd.BuildConfigFromFlags("", serverKubeconfig)
+			close(informerDone)
+			log.Info().Msg("Deployment informer disabled")
 		}
-		if err != nil {
-			log.Error().Err(err).Msg("Failed to build Kubernetes config for controller-runtime manager")
-			os.Exit(1)
+		if serverEnableInformer && watched["pods"] {
+			go informer.StartPodInformer(ctx, clientset, serverNamespace)
+		}
+		if serverEnableInformer && watched["replicasets"] {
+			go func() {
+				if err := informer.RunReplicaSetInformer(ctx, clientset, serverNamespace); err != nil {
+					exitWithError("ReplicaSet informer failed to start", err)
+				}
+			}()
 		}
 
 		// Start controller-runtime manager and controller
@@ -64,82 +131,182 @@ var serverCmd = &cobra.Command{
 			LeaderElection:          enableLeaderElection,
 			LeaderElectionID:        "k8s-controller-leader-election",
 			LeaderElectionNamespace: leaderElectionNamespace,
-			Metrics:                 server.Options{BindAddress: fmt.Sprintf(":%d", metricsPort)},
+			// The controller-runtime registry is served by the FastHTTP servers below.
+			Metrics: server.Options{BindAddress: "0"},
 		})
 		if err != nil {
-			log.Error().Err(err).Msg("Failed to create controller manager")
-			os.Exit(1)
+			exitWithError("Failed to create controller manager", err)
 		}
 
-		if err := ctrl.AddDeploymentController(mgr); err != nil {
-			log.Error().Err(err).Msg("Failed to add deployment controller")
-			os.Exit(1)
+		ctrlOpts := ctrl.Options{
+			DryRun:            controllerDryRun,
+			ReconcileDebounce: serverReconcileDebounce,
+			StatusConfigMap:   types.NamespacedName{Namespace: serverStatusNamespace, Name: serverStatusConfigMap},
+			FieldManager:      serverFieldManager,
+		}
+		if serverConsoleEvents {
+			ctrlOpts.ConsoleEvents = os.Stdout
+		}
+		if err := ctrl.AddDeploymentController(mgr, ctrlOpts); err != nil {
+			exitWithError("Failed to add deployment controller", err)
 		}
 
 		go func() {
 			log.Info().Msg("Starting controller-runtime manager...")
-			if err := mgr.Start(cmd.Context()); err != nil {
-				log.Error().Err(err).Msg("Manager exited with error")
-				os.Exit(1)
+			if err := mgr.Start(ctx); err != nil {
+				exitWithError("Manager exited with error", err)
 			}
 		}()
 
-		handler := func(ctx *fasthttp.RequestCtx) {
-			requestID := uuid.New().String()
-			ctx.Response.Header.Set("X-Request-ID", requestID)
-			logger := log.With().Str("request_id", requestID).Logger()
-			switch string(ctx.Path()) {
-			case "/deployments":
-				logger.Info().Msg("Deployments request received")
-				ctx.Response.Header.Set("Content-Type", "application/json")
-				deployments := informer.GetDeploymentNames()
-				logger.Info().Msgf("Deployments: %v", deployments)
-				ctx.SetStatusCode(200)
-				ctx.Write([]byte("["))
-				for i, name := range deployments {
-					ctx.WriteString("\"")
-					ctx.WriteString(name)
-					ctx.WriteString("\"")
-					if i < len(deployments)-1 {
-						ctx.WriteString(",")
-					}
+		handlerOpts := serverHandlerOptions{APIToken: serverAPIToken, FieldManager: serverFieldManager, MetricsOnly: serverMetricsOnly}
+		if watchDeployments {
+			handlerOpts.Readiness = informerReadiness
+		}
+		if watchDeployments && serverWaitForSync {
+			log.Info().Msg("Serving 503 on /deployments until the deployment informer cache has synced")
+			handlerOpts.CacheSynced = informer.DeploymentsSynced
+		}
+		metricsDone := make(chan struct{})
+		if separateMetricsPort() {
+			metricsOpts := serverHandlerOptions{Metrics: metricsHandler(), MetricsOnly: true, Readiness: handlerOpts.Readiness}
+			metricsServer := newHTTPServer(newServerHandler(clientset, metricsOpts))
+			metricsAddr := fmt.Sprintf(":%d", metricsPort)
+			log.Info().Msgf("Serving /metrics, /healthz and /readyz on %s", metricsAddr)
+			go func() {
+				<-ctx.Done()
+				_ = metricsServer.Shutdown()
+			}()
+			go func() {
+				defer close(metricsDone)
+				if err := metricsServer.ListenAndServe(metricsAddr); err != nil {
+					exitWithError("Error starting metrics server", err)
 				}
-				ctx.Write([]byte("]"))
-				return
-			default:
-				logger.Info().Msg("Default request received")
-				fmt.Fprintf(ctx, "Hello from FastHTTP!")
-			}
+			}()
+		} else {
+			close(metricsDone)
+			handlerOpts.Metrics = metricsHandler()
 		}
+
+		handler := traceRequest(newServerHandler(clientset, handlerOpts))
 		addr := fmt.Sprintf(":%d", serverPort)
 		log.Info().Msgf("Starting FastHTTP server on %s (version: %s)", addr, appVersion)
-		if err := fasthttp.ListenAndServe(addr, handler); err != nil {
-			log.Error().Err(err).Msg("Error starting FastHTTP server")
-			os.Exit(1)
+		httpServer := newHTTPServer(handler)
+		go func() {
+			<-ctx.Done()
+			log.Info().Msg("Shutting down FastHTTP server...")
+			_ = httpServer.Shutdown()
+		}()
+		if err := httpServer.ListenAndServe(addr); err != nil {
+			exitWithError("Error starting FastHTTP server", err)
 		}
+		<-metricsDone
+		<-informerDone
 	},
 }
 
-func getServerKubeClient(kubeconfigPath string, inCluster bool) (*kubernetes.Clientset, error) {
-	var config *rest.Config
-	var err error
-	if inCluster {
-		config, err = rest.InClusterConfig()
-	} else {
-		config, err = clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+// startDeploymentInformer runs the deployment informer until ctx is done, giving it
+// --informer-start-timeout to sync. A failure to start is only returned with
+// --informer-required; otherwise it is logged and the server keeps serving HTTP, with
+// /readyz reporting the informer as not synced, while the informer keeps retrying and
+// startDeploymentInformer still returns only once it has stopped.
+func startDeploymentInformer(ctx context.Context, cfg informer.InformerConfig) error {
+	if serverInformerRequired {
+		return informer.RunDeploymentInformer(ctx, cfg, serverInformerStartTimeout, nil)
+	}
+	logFailure := func(err error) {
+		log.Error().Err(err).Msg("Deployment informer failed to start, serving HTTP without it (use --informer-required to exit instead)")
+	}
+	if err := informer.RunDeploymentInformer(ctx, cfg, serverInformerStartTimeout, logFailure); err != nil {
+		logFailure(err)
+	}
+	return nil
+}
+
+// informerReadiness reports the deployment informer as not ready until it has synced,
+// and again whenever its cache goes stale.
+func informerReadiness() error {
+	if !informer.DeploymentsSynced() {
+		return errors.New("deployment informer cache not synced")
+	}
+	if informer.DeploymentsDegraded() {
+		return fmt.Errorf("deployment informer cache degraded: no events or resyncs within %s", serverInformerStaleThreshold)
+	}
+	return nil
+}
+
+// parseWatchedResources checks the --watch values against watchableResources.
+func parseWatchedResources(resources []string) (map[string]bool, error) {
+	watched := make(map[string]bool, len(resources))
+	for _, r := range resources {
+		r = strings.ToLower(strings.TrimSpace(r))
+		if !slices.Contains(watchableResources, r) {
+			return nil, fmt.Errorf("unknown resource %q (supported: %s)", r, strings.Join(watchableResources, ", "))
+		}
+		watched[r] = true
 	}
-	if err != nil {
-		return nil, err
+	return watched, nil
+}
+
+// separateMetricsPort reports whether /metrics gets its own listener. With --metrics-port
+// 0 or equal to --port everything is served on the app port.
+func separateMetricsPort() bool {
+	return metricsPort != 0 && metricsPort != serverPort
+}
+
+// metricsHandler serves the controller-runtime metrics registry, which also holds the
+// controller's own metrics, in the Prometheus text format.
+func metricsHandler() fasthttp.RequestHandler {
+	return fasthttpadaptor.NewFastHTTPHandler(promhttp.HandlerFor(crmetrics.Registry, promhttp.HandlerOpts{}))
+}
+
+// newHTTPServer applies the connection timeouts and request size limits configured by flags.
+func newHTTPServer(handler fasthttp.RequestHandler) *fasthttp.Server {
+	return &fasthttp.Server{
+		Handler:            handler,
+		ReadTimeout:        serverReadTimeout,
+		WriteTimeout:       serverWriteTimeout,
+		IdleTimeout:        serverIdleTimeout,
+		MaxRequestBodySize: serverMaxRequestBodySize,
+	}
+}
+
+// getServerKubeConfig returns the in-cluster config or one loaded like the CLI commands do,
+// so a colon-separated KUBECONFIG is merged when no explicit path is given.
+func getServerKubeConfig(kubeconfigPath string, inCluster bool) (*rest.Config, error) {
+	if inCluster {
+		return rest.InClusterConfig()
 	}
-	return kubernetes.NewForConfig(config)
+	return loadKubeConfig(kubeconfigPath, "")
 }
 
 func init() {
 	rootCmd.AddCommand(serverCmd)
 	serverCmd.Flags().IntVar(&serverPort, "port", 8080, "Port to run the server on")
-	serverCmd.Flags().StringVar(&serverKubeconfig, "kubeconfig", "", "Path to the kubeconfig file (defaults to KUBECONFIG environment variable if not specified)")
+	serverCmd.Flags().StringVar(&serverKubeconfig, "kubeconfig", "", "Path to the kubeconfig file (defaults to the merged KUBECONFIG files, then $HOME/.kube/config)")
 	serverCmd.Flags().BoolVar(&serverInCluster, "in-cluster", false, "Use in-cluster Kubernetes config")
 	serverCmd.Flags().BoolVar(&enableLeaderElection, "enable-leader-election", true, "Enable leader election for controller manager")
 	serverCmd.Flags().StringVar(&leaderElectionNamespace, "leader-election-namespace", "default", "Namespace for leader election")
-	serverCmd.Flags().IntVar(&metricsPort, "metrics-port", 8081, "Port for controller manager metrics")
+	serverCmd.Flags().IntVar(&metricsPort, "metrics-port", 8081, "Port of a separate listener serving only /metrics, /healthz and /readyz (0 or the value of --port serves them on the app port)")
+	serverCmd.Flags().StringVar(&serverConfigFile, "config", "", "Path to a YAML config file with server settings (flags override file values, K8S_CONTROLLER_* env vars override both)")
+	serverCmd.Flags().BoolVar(&serverEnableInformer, "enable-informer", true, "Start the informers selected with --watch; the deployment informer backs the /deployments endpoint")
+	serverCmd.Flags().StringSliceVar(&serverWatch, "watch", []string{"deployments"}, "Resources watched and logged by informers: "+strings.Join(watchableResources, ", "))
+	serverCmd.Flags().BoolVar(&serverWaitForSync, "wait-for-sync", true, "Answer data routes with 503 until the informer cache has synced")
+	serverCmd.Flags().DurationVar(&serverInformerStartTimeout, "informer-start-timeout", 30*time.Second, "How long to wait for the deployment informer to sync at startup before giving up on it (0 waits forever)")
+	serverCmd.Flags().BoolVar(&serverInformerRequired, "informer-required", false, "Exit when the deployment informer fails to start instead of serving HTTP without it")
+	serverCmd.Flags().DurationVar(&serverInformerStaleThreshold, "informer-stale-threshold", 5*time.Minute, "Report /readyz as not ready when the informer has seen no events or resyncs for this long (0 disables)")
+	serverCmd.Flags().StringVar(&serverWebhookURL, "webhook-url", "", "URL that receives a JSON POST for every deployment event seen by the informer (disabled when empty)")
+	serverCmd.Flags().StringVar(&serverNamespace, "namespace", "default", "Namespace watched by the deployment informer (empty for all namespaces)")
+	serverCmd.Flags().DurationVar(&serverReadTimeout, "read-timeout", 10*time.Second, "Maximum duration for reading a full request, including the body")
+	serverCmd.Flags().DurationVar(&serverWriteTimeout, "write-timeout", 10*time.Second, "Maximum duration for writing a response")
+	serverCmd.Flags().DurationVar(&serverIdleTimeout, "idle-timeout", 60*time.Second, "Maximum time to keep an idle keep-alive connection open")
+	serverCmd.Flags().IntVar(&serverMaxRequestBodySize, "max-request-body-size", fasthttp.DefaultMaxRequestBodySize, "Maximum request body size in bytes")
+	serverCmd.Flags().BoolVar(&serverConsoleEvents, "console-events", false, "Also print a short human-readable line to stdout for every reconcile, e.g. for tutorials")
+	serverCmd.Flags().DurationVar(&serverReconcileDebounce, "reconcile-debounce", 0, "Coalesce updates to the same deployment within this window into one reconcile, e.g. 2s (0 reconciles every update)")
+	serverCmd.Flags().StringVar(&serverStatusConfigMap, "status-configmap", "", "Name of a ConfigMap the controller keeps up to date with the health of every deployment, e.g. k8s-controller-status (disabled when empty)")
+	serverCmd.Flags().StringVar(&serverStatusNamespace, "status-configmap-namespace", "default", "Namespace of the --status-configmap ConfigMap")
+	serverCmd.Flags().BoolVar(&controllerDryRun, "dry-run", false, "Run the deployment controller in observe-only mode, logging writes instead of applying them")
+	serverCmd.Flags().StringVar(&serverFieldManager, "field-manager", defaultFieldManager, "Name recorded as the manager of fields written by the server in managedFields")
+	serverCmd.Flags().BoolVar(&serverMetricsOnly, "metrics-only", false, "Serve only operational endpoints (/healthz, /readyz and the metrics port); other HTTP routes return 404")
+	serverCmd.Flags().StringVar(&serverOtelEndpoint, "otel-endpoint", "", "OTLP gRPC collector URL for traces, e.g. http://otel-collector:4317 (tracing is disabled when empty)")
+	serverCmd.Flags().StringVar(&serverAPIToken, "api-token", "", "Bearer token required by write endpoints such as deployment scaling (write endpoints are disabled when empty)")
 }
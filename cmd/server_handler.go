@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/valyala/fasthttp"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/informer"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var serverAPIToken string
+
+// scaleRequest is the body accepted by PUT /deployments/{namespace}/{name}/scale.
+type scaleRequest struct {
+	Replicas *int32 `json:"replicas"`
+}
+
+// serverHandlerOptions configures the routes served by newServerHandler.
+type serverHandlerOptions struct {
+	// APIToken is the bearer token required by write endpoints; empty disables them.
+	APIToken string
+	// CacheSynced, when set, gates data routes with 503 until it returns true.
+	CacheSynced func() bool
+	// FieldManager is recorded in managedFields for writes made by the server.
+	FieldManager string
+	// Metrics, when set, serves /metrics.
+	Metrics fasthttp.RequestHandler
+	// MetricsOnly serves only operational endpoints such as /healthz; every other route is 404.
+	MetricsOnly bool
+	// Readiness, when set, backs /readyz; a non-nil error reports the server as not ready.
+	Readiness func() error
+}
+
+// newServerHandler routes the server's HTTP API. Read endpoints are served from the
+// informer cache; write endpoints go to the API server through clientset and require
+// the bearer token configured with --api-token.
+func newServerHandler(clientset kubernetes.Interface, opts serverHandlerOptions) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		requestID := uuid.New().String()
+		ctx.Response.Header.Set("X-Request-ID", requestID)
+		logger := log.With().Str("request_id", requestID).Logger()
+
+		path := string(ctx.Path())
+		if path == "/healthz" {
+			ctx.SetStatusCode(fasthttp.StatusOK)
+			ctx.WriteString("ok")
+			return
+		}
+		if path == "/readyz" {
+			handleReadyz(ctx, logger, opts.Readiness)
+			return
+		}
+		if path == "/metrics" && opts.Metrics != nil {
+			opts.Metrics(ctx)
+			return
+		}
+		if opts.MetricsOnly {
+			ctx.SetStatusCode(fasthttp.StatusNotFound)
+			return
+		}
+		if ns, name, ok := parseScalePath(path); ok {
+			requireToken(opts.APIToken, logger, func(ctx *fasthttp.RequestCtx) {
+				handleScaleDeployment(ctx, logger, clientset, ns, name, opts.FieldManager)
+			})(ctx)
+			return
+		}
+
+		switch path {
+		case "/deployments":
+			logger.Info().Msg("Deployments request received")
+			if opts.CacheSynced != nil && !opts.CacheSynced() {
+				logger.Warn().Msg("Deployment cache not synced yet")
+				ctx.Response.Header.Set("Retry-After", "1")
+				writeJSONError(ctx, fasthttp.StatusServiceUnavailable, "deployment cache is still syncing")
+				return
+			}
+			ctx.Response.Header.Set("Content-Type", "application/json")
+			deployments := informer.GetDeploymentNames()
+			logger.Info().Msgf("Deployments: %v", deployments)
+			ctx.SetStatusCode(200)
+			ctx.Write([]byte("["))
+			for i, name := range deployments {
+				ctx.WriteString("\"")
+				ctx.WriteString(name)
+				ctx.WriteString("\"")
+				if i < len(deployments)-1 {
+					ctx.WriteString(",")
+				}
+			}
+			ctx.Write([]byte("]"))
+			return
+		default:
+			logger.Info().Msg("Default request received")
+			fmt.Fprintf(ctx, "Hello from FastHTTP!")
+		}
+	}
+}
+
+func handleReadyz(ctx *fasthttp.RequestCtx, logger zerolog.Logger, readiness func() error) {
+	if readiness != nil {
+		if err := readiness(); err != nil {
+			logger.Warn().Err(err).Msg("Readiness check failed")
+			ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+			ctx.WriteString(err.Error())
+			return
+		}
+	}
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.WriteString("ok")
+}
+
+// parseScalePath extracts namespace and name from /deployments/{namespace}/{name}/scale.
+func parseScalePath(path string) (string, string, bool) {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(parts) != 4 || parts[0] != "deployments" || parts[3] != "scale" || parts[1] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+// requireToken rejects requests that do not carry "Authorization: Bearer <token>".
+// Without a configured token protected endpoints are disabled entirely.
+func requireToken(token string, logger zerolog.Logger, next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		if token == "" {
+			logger.Warn().Msg("Rejected request to protected endpoint: no API token configured")
+			writeJSONError(ctx, fasthttp.StatusForbidden, "write endpoints are disabled; start the server with --api-token")
+			return
+		}
+		given, ok := strings.CutPrefix(string(ctx.Request.Header.Peek("Authorization")), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			logger.Warn().Msg("Rejected request to protected endpoint: invalid bearer token")
+			ctx.Response.Header.Set("WWW-Authenticate", "Bearer")
+			writeJSONError(ctx, fasthttp.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+		next(ctx)
+	}
+}
+
+func handleScaleDeployment(ctx *fasthttp.RequestCtx, logger zerolog.Logger, clientset kubernetes.Interface, ns, name, fieldManager string) {
+	if !ctx.IsPut() {
+		ctx.Response.Header.Set("Allow", fasthttp.MethodPut)
+		writeJSONError(ctx, fasthttp.StatusMethodNotAllowed, "only PUT is supported")
+		return
+	}
+	var req scaleRequest
+	if err := json.Unmarshal(ctx.PostBody(), &req); err != nil {
+		writeJSONError(ctx, fasthttp.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	if req.Replicas == nil {
+		writeJSONError(ctx, fasthttp.StatusBadRequest, "replicas is required")
+		return
+	}
+	if *req.Replicas < 0 {
+		writeJSONError(ctx, fasthttp.StatusBadRequest, "replicas must be >= 0")
+		return
+	}
+
+	logger.Info().Str("namespace", ns).Str("name", name).Int32("replicas", *req.Replicas).Msg("Scale request received")
+	deployments := clientset.AppsV1().Deployments(ns)
+	reqCtx := requestContext(ctx)
+	var scale *autoscalingv1.Scale
+	err := traceAPICall(reqCtx, "GetScale", func(apiCtx context.Context) (err error) {
+		scale, err = deployments.GetScale(apiCtx, name, metav1.GetOptions{})
+		return err
+	})
+	if err != nil {
+		writeAPIError(ctx, logger, err)
+		return
+	}
+	scale.Spec.Replicas = *req.Replicas
+	var updated *autoscalingv1.Scale
+	err = traceAPICall(reqCtx, "UpdateScale", func(apiCtx context.Context) (err error) {
+		updated, err = deployments.UpdateScale(apiCtx, name, scale, metav1.UpdateOptions{FieldManager: fieldManager})
+		return err
+	})
+	if err != nil {
+		writeAPIError(ctx, logger, err)
+		return
+	}
+
+	body, err := json.Marshal(updated)
+	if err != nil {
+		writeJSONError(ctx, fasthttp.StatusInternalServerError, err.Error())
+		return
+	}
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	ctx.SetStatusCode(fasthttp.StatusOK)
+	ctx.Write(body)
+}
+
+// writeAPIError passes the status code of a Kubernetes API error through to the client.
+func writeAPIError(ctx *fasthttp.RequestCtx, logger zerolog.Logger, err error) {
+	code := http.StatusInternalServerError
+	if status, ok := err.(apierrors.APIStatus); ok && status.Status().Code != 0 {
+		code = int(status.Status().Code)
+	}
+	logger.Error().Err(err).Int("status", code).Msg("Kubernetes API request failed")
+	writeJSONError(ctx, code, err.Error())
+}
+
+func writeJSONError(ctx *fasthttp.RequestCtx, code int, message string) {
+	body, _ := json.Marshal(map[string]string{"error": message})
+	ctx.Response.Header.Set("Content-Type", "application/json")
+	ctx.SetStatusCode(code)
+	ctx.Write(body)
+}
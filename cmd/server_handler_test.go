@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func serveTestRequest(handler fasthttp.RequestHandler, method, uri, token, body string) *fasthttp.RequestCtx {
+	var ctx fasthttp.RequestCtx
+	ctx.Request.Header.SetMethod(method)
+	ctx.Request.SetRequestURI(uri)
+	if token != "" {
+		ctx.Request.Header.Set("Authorization", "Bearer "+token)
+	}
+	ctx.Request.SetBodyString(body)
+	handler(&ctx)
+	return &ctx
+}
+
+func newScaleTestClientset() *fake.Clientset {
+	replicas := int32(1)
+	clientset := fake.NewClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+	})
+	clientset.PrependReactor("get", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "scale" {
+			return false, nil, nil
+		}
+		get := action.(k8stesting.GetAction)
+		if get.GetName() != "web" {
+			return false, nil, nil
+		}
+		return true, &autoscalingv1.Scale{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: get.GetNamespace()},
+			Spec:       autoscalingv1.ScaleSpec{Replicas: replicas},
+		}, nil
+	})
+	clientset.PrependReactor("update", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "scale" {
+			return false, nil, nil
+		}
+		update := action.(k8stesting.UpdateActionImpl)
+		if update.GetUpdateOptions().FieldManager != "k8s-controller" {
+			return true, nil, apierrors.NewBadRequest("unexpected field manager " + update.GetUpdateOptions().FieldManager)
+		}
+		scale := update.GetObject().(*autoscalingv1.Scale)
+		replicas = scale.Spec.Replicas
+		return true, scale, nil
+	})
+	return clientset
+}
+
+func TestScaleEndpoint(t *testing.T) {
+	handler := newServerHandler(newScaleTestClientset(), serverHandlerOptions{APIToken: "secret", FieldManager: defaultFieldManager})
+
+	ctx := serveTestRequest(handler, fasthttp.MethodPut, "/deployments/default/web/scale", "secret", `{"replicas": 3}`)
+	if ctx.Response.StatusCode() != fasthttp.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", ctx.Response.StatusCode(), ctx.Response.Body())
+	}
+	var scale autoscalingv1.Scale
+	if err := json.Unmarshal(ctx.Response.Body(), &scale); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if scale.Spec.Replicas != 3 {
+		t.Errorf("replicas = %d, want 3", scale.Spec.Replicas)
+	}
+}
+
+func TestScaleEndpoint_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		method string
+		uri    string
+		body   string
+		want   int
+	}{
+		{"no token", "", fasthttp.MethodPut, "/deployments/default/web/scale", `{"replicas": 3}`, fasthttp.StatusUnauthorized},
+		{"wrong token", "nope", fasthttp.MethodPut, "/deployments/default/web/scale", `{"replicas": 3}`, fasthttp.StatusUnauthorized},
+		{"wrong method", "secret", fasthttp.MethodPost, "/deployments/default/web/scale", `{"replicas": 3}`, fasthttp.StatusMethodNotAllowed},
+		{"negative replicas", "secret", fasthttp.MethodPut, "/deployments/default/web/scale", `{"replicas": -1}`, fasthttp.StatusBadRequest},
+		{"missing replicas", "secret", fasthttp.MethodPut, "/deployments/default/web/scale", `{}`, fasthttp.StatusBadRequest},
+		{"invalid body", "secret", fasthttp.MethodPut, "/deployments/default/web/scale", `replicas=3`, fasthttp.StatusBadRequest},
+		{"not found", "secret", fasthttp.MethodPut, "/deployments/default/missing/scale", `{"replicas": 3}`, fasthttp.StatusNotFound},
+	}
+	handler := newServerHandler(newScaleTestClientset(), serverHandlerOptions{APIToken: "secret", FieldManager: defaultFieldManager})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := serveTestRequest(handler, tt.method, tt.uri, tt.token, tt.body)
+			if ctx.Response.StatusCode() != tt.want {
+				t.Errorf("status = %d, want %d: %s", ctx.Response.StatusCode(), tt.want, ctx.Response.Body())
+			}
+		})
+	}
+}
+
+func TestScaleEndpoint_DisabledWithoutToken(t *testing.T) {
+	handler := newServerHandler(newScaleTestClientset(), serverHandlerOptions{})
+	ctx := serveTestRequest(handler, fasthttp.MethodPut, "/deployments/default/web/scale", "", `{"replicas": 3}`)
+	if ctx.Response.StatusCode() != fasthttp.StatusForbidden {
+		t.Errorf("status = %d, want %d", ctx.Response.StatusCode(), fasthttp.StatusForbidden)
+	}
+}
+
+func TestDeploymentsEndpoint_WaitsForSync(t *testing.T) {
+	synced := false
+	handler := newServerHandler(fake.NewClientset(), serverHandlerOptions{CacheSynced: func() bool { return synced }})
+
+	ctx := serveTestRequest(handler, fasthttp.MethodGet, "/deployments", "", "")
+	if ctx.Response.StatusCode() != fasthttp.StatusServiceUnavailable {
+		t.Errorf("status before sync = %d, want %d", ctx.Response.StatusCode(), fasthttp.StatusServiceUnavailable)
+	}
+
+	synced = true
+	ctx = serveTestRequest(handler, fasthttp.MethodGet, "/deployments", "", "")
+	if ctx.Response.StatusCode() != fasthttp.StatusOK {
+		t.Errorf("status after sync = %d, want %d", ctx.Response.StatusCode(), fasthttp.StatusOK)
+	}
+
+	ctx = serveTestRequest(handler, fasthttp.MethodGet, "/", "", "")
+	if ctx.Response.StatusCode() != fasthttp.StatusOK {
+		t.Errorf("default route status = %d, want %d", ctx.Response.StatusCode(), fasthttp.StatusOK)
+	}
+}
+
+func TestServerHandler_MetricsOnly(t *testing.T) {
+	handler := newServerHandler(fake.NewClientset(), serverHandlerOptions{MetricsOnly: true, APIToken: "secret"})
+
+	for _, uri := range []string{"/", "/deployments", "/deployments/default/web/scale"} {
+		ctx := serveTestRequest(handler, fasthttp.MethodGet, uri, "secret", "")
+		if ctx.Response.StatusCode() != fasthttp.StatusNotFound {
+			t.Errorf("%s status = %d, want %d", uri, ctx.Response.StatusCode(), fasthttp.StatusNotFound)
+		}
+	}
+
+	ctx := serveTestRequest(handler, fasthttp.MethodGet, "/healthz", "", "")
+	if ctx.Response.StatusCode() != fasthttp.StatusOK {
+		t.Errorf("/healthz status = %d, want %d", ctx.Response.StatusCode(), fasthttp.StatusOK)
+	}
+}
+
+func TestServerHandler_Metrics(t *testing.T) {
+	metrics := func(ctx *fasthttp.RequestCtx) { ctx.WriteString("metrics") }
+
+	handler := newServerHandler(fake.NewClientset(), serverHandlerOptions{Metrics: metrics, MetricsOnly: true})
+	ctx := serveTestRequest(handler, fasthttp.MethodGet, "/metrics", "", "")
+	if got := string(ctx.Response.Body()); got != "metrics" {
+		t.Errorf("/metrics body = %q, want the metrics handler output", got)
+	}
+
+	// Without a metrics handler /metrics is an ordinary route.
+	handler = newServerHandler(fake.NewClientset(), serverHandlerOptions{MetricsOnly: true})
+	ctx = serveTestRequest(handler, fasthttp.MethodGet, "/metrics", "", "")
+	if ctx.Response.StatusCode() != fasthttp.StatusNotFound {
+		t.Errorf("/metrics status = %d, want %d", ctx.Response.StatusCode(), fasthttp.StatusNotFound)
+	}
+}
+
+func TestReadyzEndpoint(t *testing.T) {
+	var readyErr error
+	handler := newServerHandler(fake.NewClientset(), serverHandlerOptions{Readiness: func() error { return readyErr }})
+
+	ctx := serveTestRequest(handler, fasthttp.MethodGet, "/readyz", "", "")
+	if ctx.Response.StatusCode() != fasthttp.StatusOK {
+		t.Errorf("ready status = %d, want %d", ctx.Response.StatusCode(), fasthttp.StatusOK)
+	}
+
+	readyErr = errors.New("deployment informer cache degraded")
+	ctx = serveTestRequest(handler, fasthttp.MethodGet, "/readyz", "", "")
+	if ctx.Response.StatusCode() != fasthttp.StatusServiceUnavailable {
+		t.Errorf("degraded status = %d, want %d", ctx.Response.StatusCode(), fasthttp.StatusServiceUnavailable)
+	}
+}
@@ -1,7 +1,20 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/valyala/fasthttp"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/informer"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func TestServerCommandDefined(t *testing.T) {
@@ -17,9 +30,194 @@ func TestServerCommandDefined(t *testing.T) {
 	}
 }
 
-func TestGetServerKubeClient_InvalidPath(t *testing.T) {
-	_, err := getServerKubeClient("/invalid/path", false)
+func TestGetServerKubeConfig_InvalidPath(t *testing.T) {
+	_, err := getServerKubeConfig("/invalid/path", false)
 	if err == nil {
 		t.Error("expected error for invalid kubeconfig path")
 	}
 }
+
+func TestServerTimeoutFlagDefaults(t *testing.T) {
+	for name, want := range map[string]string{"read-timeout": "10s", "write-timeout": "10s", "idle-timeout": "1m0s"} {
+		flag := serverCmd.Flags().Lookup(name)
+		if flag == nil {
+			t.Errorf("expected %q flag to be defined", name)
+			continue
+		}
+		if flag.DefValue != want {
+			t.Errorf("%s default = %s, want %s", name, flag.DefValue, want)
+		}
+	}
+}
+
+func TestNewHTTPServer_AppliesFlags(t *testing.T) {
+	originalRead, originalWrite, originalIdle, originalBody := serverReadTimeout, serverWriteTimeout, serverIdleTimeout, serverMaxRequestBodySize
+	defer func() {
+		serverReadTimeout, serverWriteTimeout, serverIdleTimeout, serverMaxRequestBodySize = originalRead, originalWrite, originalIdle, originalBody
+	}()
+	serverReadTimeout = 5 * time.Second
+	serverIdleTimeout = 30 * time.Second
+	serverMaxRequestBodySize = 1024
+
+	srv := newHTTPServer(func(ctx *fasthttp.RequestCtx) {})
+	if srv.ReadTimeout != 5*time.Second {
+		t.Errorf("ReadTimeout = %s, want 5s", srv.ReadTimeout)
+	}
+	if srv.WriteTimeout != originalWrite {
+		t.Errorf("WriteTimeout = %s, want %s", srv.WriteTimeout, originalWrite)
+	}
+	if srv.IdleTimeout != 30*time.Second {
+		t.Errorf("IdleTimeout = %s, want 30s", srv.IdleTimeout)
+	}
+	if srv.MaxRequestBodySize != 1024 {
+		t.Errorf("MaxRequestBodySize = %d, want 1024", srv.MaxRequestBodySize)
+	}
+}
+
+func TestSeparateMetricsPort(t *testing.T) {
+	originalPort, originalMetricsPort := serverPort, metricsPort
+	defer func() { serverPort, metricsPort = originalPort, originalMetricsPort }()
+
+	for _, tt := range []struct {
+		port, metricsPort int
+		want              bool
+	}{
+		{8080, 8081, true},
+		{8080, 8080, false},
+		{8080, 0, false},
+	} {
+		serverPort, metricsPort = tt.port, tt.metricsPort
+		if got := separateMetricsPort(); got != tt.want {
+			t.Errorf("separateMetricsPort() with --port %d --metrics-port %d = %v, want %v", tt.port, tt.metricsPort, got, tt.want)
+		}
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	var ctx fasthttp.RequestCtx
+	ctx.Request.SetRequestURI("/metrics")
+	metricsHandler()(&ctx)
+	if ctx.Response.StatusCode() != fasthttp.StatusOK {
+		t.Fatalf("status = %d, want %d", ctx.Response.StatusCode(), fasthttp.StatusOK)
+	}
+	if ct := string(ctx.Response.Header.ContentType()); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q, want the Prometheus text format", ct)
+	}
+}
+
+func TestParseWatchedResources(t *testing.T) {
+	watched, err := parseWatchedResources([]string{"deployments", " ReplicaSets"})
+	if err != nil {
+		t.Fatalf("parseWatchedResources returned error: %v", err)
+	}
+	if !watched["deployments"] || !watched["replicasets"] || watched["pods"] {
+		t.Errorf("watched = %v, want deployments and replicasets", watched)
+	}
+
+	if _, err := parseWatchedResources([]string{"services"}); err == nil || !strings.Contains(err.Error(), "replicasets") {
+		t.Errorf("expected an error listing the supported resources, got %v", err)
+	}
+}
+
+func TestStartDeploymentInformer_TimeoutTolerated(t *testing.T) {
+	originalTimeout, originalRequired := serverInformerStartTimeout, serverInformerRequired
+	defer func() { serverInformerStartTimeout, serverInformerRequired = originalTimeout, originalRequired }()
+	serverInformerStartTimeout = 100 * time.Millisecond
+	serverInformerRequired = false
+
+	var logs lockedBuffer
+	originalLogger := log.Logger
+	log.Logger = zerolog.New(&logs)
+	defer func() { log.Logger = originalLogger }()
+
+	clientset, reachable := unreachableDeploymentsClientset()
+	cfg := informer.InformerConfig{Clientset: clientset, Namespace: "default"}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- startDeploymentInformer(ctx, cfg) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(logs.String(), "failed to start, serving HTTP without it") {
+		if time.Now().After(deadline) {
+			t.Fatalf("start timeout was not logged:\n%s", logs.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("startDeploymentInformer returned %v before ctx was done", err)
+	default:
+	}
+
+	// The informer keeps retrying after the timeout and syncs once the API server answers.
+	reachable.Store(true)
+	deadline = time.Now().Add(10 * time.Second)
+	for !informer.DeploymentsSynced() {
+		if time.Now().After(deadline) {
+			t.Fatal("deployment informer did not sync after the API server became reachable")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("expected the failure to be tolerated without --informer-required, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("startDeploymentInformer did not return after ctx was done")
+	}
+	if !strings.Contains(logs.String(), "Deployment informer stopped") {
+		t.Errorf("expected the informer summary before startDeploymentInformer returned:\n%s", logs.String())
+	}
+}
+
+func TestStartDeploymentInformer_Timeout(t *testing.T) {
+	originalTimeout, originalRequired := serverInformerStartTimeout, serverInformerRequired
+	defer func() { serverInformerStartTimeout, serverInformerRequired = originalTimeout, originalRequired }()
+	serverInformerStartTimeout = 100 * time.Millisecond
+	serverInformerRequired = true
+
+	var logs lockedBuffer
+	originalLogger := log.Logger
+	log.Logger = zerolog.New(&logs)
+	defer func() { log.Logger = originalLogger }()
+
+	clientset, _ := unreachableDeploymentsClientset()
+	cfg := informer.InformerConfig{Clientset: clientset, Namespace: "default"}
+	ctx, cancel := context.WithCancel(context.Background())
+	// The informer is left retrying in the background; let it stop before the logger is restored.
+	defer func() {
+		cancel()
+		deadline := time.Now().Add(5 * time.Second)
+		for !strings.Contains(logs.String(), "Deployment informer stopped") && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	start := time.Now()
+	err := startDeploymentInformer(ctx, cfg)
+	if err == nil || !strings.Contains(err.Error(), "did not sync within 100ms") || !strings.Contains(err.Error(), "connection refused") {
+		t.Errorf("expected a sync timeout carrying the list error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("startDeploymentInformer took %s despite the start timeout", elapsed)
+	}
+}
+
+// unreachableDeploymentsClientset refuses deployment lists, so the informer cannot sync,
+// until the returned flag is set.
+func unreachableDeploymentsClientset() (*fake.Clientset, *atomic.Bool) {
+	reachable := &atomic.Bool{}
+	clientset := fake.NewClientset()
+	clientset.PrependReactor("list", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if reachable.Load() {
+			return false, nil, nil
+		}
+		return true, nil, errors.New("connection refused")
+	})
+	return clientset, reachable
+}
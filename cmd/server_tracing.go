@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var serverTracer = otel.Tracer("github.com/yourusername/k8s-controller-tutorial/cmd/server")
+
+// fasthttpHeaderCarrier adapts fasthttp request headers for trace context propagation.
+type fasthttpHeaderCarrier struct {
+	header *fasthttp.RequestHeader
+}
+
+func (c fasthttpHeaderCarrier) Get(key string) string { return string(c.header.Peek(key)) }
+
+func (c fasthttpHeaderCarrier) Set(key, value string) { c.header.Set(key, value) }
+
+func (c fasthttpHeaderCarrier) Keys() []string {
+	var keys []string
+	c.header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
+
+var _ propagation.TextMapCarrier = fasthttpHeaderCarrier{}
+
+// requestContextKey is the fasthttp user value under which traceRequest stores the
+// context carrying the request span.
+type requestContextKey struct{}
+
+// requestContext returns the context for API calls made while serving ctx: the request
+// span's context when the request is traced, ctx itself otherwise.
+func requestContext(ctx *fasthttp.RequestCtx) context.Context {
+	if spanCtx, ok := ctx.UserValue(requestContextKey{}).(context.Context); ok {
+		return spanCtx
+	}
+	return ctx
+}
+
+// traceAPICall runs call in a client span named after the API operation, so it shows up
+// as a child of the request span carried by ctx.
+func traceAPICall(ctx context.Context, operation string, call func(context.Context) error) error {
+	ctx, span := serverTracer.Start(ctx, operation, trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+	err := call(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+// traceRequest wraps every request in a server span, continuing the caller's trace when
+// the request carries a traceparent header.
+func traceRequest(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		parent := otel.GetTextMapPropagator().Extract(ctx, fasthttpHeaderCarrier{header: &ctx.Request.Header})
+		method := string(ctx.Method())
+		attrs := []attribute.KeyValue{
+			attribute.String("http.request.method", method),
+			attribute.String("url.path", string(ctx.Path())),
+		}
+		// Unmatched paths are named after the method alone, so scanners and typos cannot
+		// create a span name per path.
+		spanName := method
+		if route, ok := requestRoute(string(ctx.Path())); ok {
+			spanName = method + " " + route
+			attrs = append(attrs, attribute.String("http.route", route))
+		}
+		spanCtx, span := serverTracer.Start(parent, spanName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attrs...),
+		)
+		defer span.End()
+		ctx.SetUserValue(requestContextKey{}, spanCtx)
+
+		next(ctx)
+
+		status := ctx.Response.StatusCode()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= fasthttp.StatusInternalServerError {
+			span.SetStatus(codes.Error, fasthttp.StatusMessage(status))
+		}
+	}
+}
+
+// requestRoute returns the route template for path so span names stay low-cardinality.
+// It reports false for paths that only reach the default handler.
+func requestRoute(path string) (string, bool) {
+	switch path {
+	case "/healthz", "/readyz", "/metrics", "/deployments":
+		return path, true
+	}
+	if _, _, ok := parseScalePath(path); ok {
+		return "/deployments/{namespace}/{name}/scale", true
+	}
+	return "", false
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTraceRequest(t *testing.T) {
+	originalProvider, originalPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(originalProvider)
+		otel.SetTextMapPropagator(originalPropagator)
+	})
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	handler := traceRequest(func(ctx *fasthttp.RequestCtx) {
+		ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+	})
+	var ctx fasthttp.RequestCtx
+	ctx.Request.Header.SetMethod(fasthttp.MethodPut)
+	ctx.Request.SetRequestURI("/deployments/default/web/scale")
+	ctx.Request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler(&ctx)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "PUT /deployments/{namespace}/{name}/scale" {
+		t.Errorf("span name = %q", span.Name())
+	}
+	if got := span.SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace ID = %s, want the caller's trace", got)
+	}
+	var status int64
+	for _, attr := range span.Attributes() {
+		if attr.Key == attribute.Key("http.response.status_code") {
+			status = attr.Value.AsInt64()
+		}
+	}
+	if status != fasthttp.StatusServiceUnavailable {
+		t.Errorf("status attribute = %d, want %d", status, fasthttp.StatusServiceUnavailable)
+	}
+
+	// Unmatched paths are named after the method alone and carry no http.route.
+	for _, uri := range []string{"/wp-login.php", "/.env", "/deployments/default"} {
+		var ctx fasthttp.RequestCtx
+		ctx.Request.SetRequestURI(uri)
+		handler(&ctx)
+	}
+	spans = recorder.Ended()[1:]
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans for unmatched paths, got %d", len(spans))
+	}
+	for _, span := range spans {
+		if span.Name() != "GET" {
+			t.Errorf("span name = %q, want the method only", span.Name())
+		}
+		for _, attr := range span.Attributes() {
+			if attr.Key == attribute.Key("http.route") {
+				t.Errorf("unexpected http.route %q on an unmatched path", attr.Value.AsString())
+			}
+		}
+	}
+
+	// API calls made by the scale handler are children of the request span.
+	scaleHandler := traceRequest(newServerHandler(newScaleTestClientset(), serverHandlerOptions{APIToken: "secret", FieldManager: defaultFieldManager}))
+	serveTestRequest(scaleHandler, fasthttp.MethodPut, "/deployments/default/web/scale", "secret", `{"replicas": 3}`)
+	spans = recorder.Ended()[4:]
+	if len(spans) != 3 {
+		t.Fatalf("expected GetScale, UpdateScale and request spans, got %d", len(spans))
+	}
+	request := spans[2]
+	for i, name := range []string{"GetScale", "UpdateScale"} {
+		if spans[i].Name() != name {
+			t.Errorf("span %d name = %q, want %q", i, spans[i].Name(), name)
+		}
+		if spans[i].Parent().SpanID() != request.SpanContext().SpanID() {
+			t.Errorf("%s is not a child of the request span", spans[i].Name())
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// changeCauseAnnotation records the command that triggered a rollout, as kubectl --record does.
+const changeCauseAnnotation = "kubernetes.io/change-cause"
+
+var setCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set specific fields on Kubernetes resources",
+}
+
+var setImageCmd = &cobra.Command{
+	Use:   "image",
+	Short: "Update the container images of a resource",
+}
+
+var setImageDeploymentCmd = &cobra.Command{
+	Use:     "deployment [name] container=image...",
+	Short:   "Update the images of a deployment's containers",
+	Aliases: []string{"deploy"},
+	Long: `Update container images in a deployment's pod template, triggering a rollout.
+Use *=image to set the image of every container.`,
+	Example: `  k8s-controller set image deployment web app=nginx:1.27
+  k8s-controller set image deployment web app=nginx:1.27 sidecar=envoy:v1.31 --record
+  k8s-controller set image deployment web '*=registry.local/web:v2'`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		record, _ := cmd.Flags().GetBool("record")
+
+		images, err := parseImageAssignments(args[1:])
+		if err != nil {
+			exitWithError("Invalid image assignment", err)
+		}
+		changeCause := ""
+		if record {
+			changeCause = strings.Join(os.Args, " ")
+		}
+
+		clientset, err := getKubeClient()
+		if err != nil {
+			exitWithError("Failed to create Kubernetes client", err)
+		}
+		updated, err := setDeploymentImages(context.Background(), clientset, namespace, name, images, changeCause)
+		if err != nil {
+			exitWithError("Failed to update deployment images", err)
+		}
+		for _, container := range updated {
+			fmt.Printf("Container '%s' of deployment '%s' updated to image '%s' in namespace '%s'\n", container, name, images.imageFor(container), namespace)
+		}
+	},
+}
+
+// imageAssignments maps container names to images; the "*" key applies to every container.
+type imageAssignments map[string]string
+
+// imageFor returns the image assigned to container, falling back to the wildcard.
+func (a imageAssignments) imageFor(container string) string {
+	if image, ok := a[container]; ok {
+		return image
+	}
+	return a["*"]
+}
+
+// parseImageAssignments parses container=image arguments.
+func parseImageAssignments(args []string) (imageAssignments, error) {
+	images := make(imageAssignments, len(args))
+	for _, arg := range args {
+		container, image, ok := strings.Cut(arg, "=")
+		if !ok || container == "" || image == "" {
+			return nil, fmt.Errorf("%q must be in the form container=image", arg)
+		}
+		if _, dup := images[container]; dup {
+			return nil, fmt.Errorf("container %q is assigned more than once", container)
+		}
+		images[container] = image
+	}
+	return images, nil
+}
+
+// setDeploymentImages patches the images of the deployment's containers and returns the
+// names of the containers that were changed. Every named container must exist in the pod
+// template. A non-empty changeCause is stored in the change-cause annotation.
+func setDeploymentImages(ctx context.Context, clientset kubernetes.Interface, ns, name string, images imageAssignments, changeCause string) ([]string, error) {
+	log.Info().Str("name", name).Str("namespace", ns).Interface("images", images).Msg("Setting deployment images")
+
+	deployments := clientset.AppsV1().Deployments(ns)
+	deployment, err := deployments.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment: %w", err)
+	}
+
+	patch, updated, err := imagePatch(deployment, images, changeCause)
+	if err != nil {
+		return nil, err
+	}
+	_, err = deployments.Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{FieldManager: fieldManager})
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch deployment: %w", err)
+	}
+	return updated, nil
+}
+
+// imagePatch builds a strategic merge patch that sets the assigned images, merging
+// containers by name so the rest of the pod template is left untouched.
+func imagePatch(deployment *appsv1.Deployment, images imageAssignments, changeCause string) ([]byte, []string, error) {
+	existing := make(map[string]bool, len(deployment.Spec.Template.Spec.Containers))
+	var names []string
+	for _, c := range deployment.Spec.Template.Spec.Containers {
+		existing[c.Name] = true
+		names = append(names, c.Name)
+	}
+	var unknown []string
+	for container := range images {
+		if container != "*" && !existing[container] {
+			unknown = append(unknown, container)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, nil, fmt.Errorf("deployment %q has no container named %s; valid containers: %s",
+			deployment.Name, strings.Join(unknown, ", "), strings.Join(names, ", "))
+	}
+
+	type containerPatch struct {
+		Name  string `json:"name"`
+		Image string `json:"image"`
+	}
+	var containers []containerPatch
+	var updated []string
+	for _, name := range names {
+		if image := images.imageFor(name); image != "" {
+			containers = append(containers, containerPatch{Name: name, Image: image})
+			updated = append(updated, name)
+		}
+	}
+
+	patch := map[string]any{
+		"spec": map[string]any{
+			"template": map[string]any{
+				"spec": map[string]any{"containers": containers},
+			},
+		},
+	}
+	if changeCause != "" {
+		patch["metadata"] = map[string]any{
+			"annotations": map[string]string{changeCauseAnnotation: changeCause},
+		}
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode patch: %w", err)
+	}
+	return data, updated, nil
+}
+
+func init() {
+	rootCmd.AddCommand(setCmd)
+	setCmd.AddCommand(setImageCmd)
+	setImageCmd.AddCommand(setImageDeploymentCmd)
+
+	setImageDeploymentCmd.Flags().Bool("record", false, "Record the command in the deployment's kubernetes.io/change-cause annotation")
+	setCmd.PersistentFlags().StringVar(&fieldManager, "field-manager", defaultFieldManager, "Name recorded as the manager of written fields in managedFields")
+}
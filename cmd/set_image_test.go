@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newSetImageTestDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{
+					{Name: "app", Image: "nginx:1.25", Ports: []corev1.ContainerPort{{ContainerPort: 80}}},
+					{Name: "sidecar", Image: "envoy:v1.30"},
+				}},
+			},
+		},
+	}
+}
+
+func TestParseImageAssignments(t *testing.T) {
+	images, err := parseImageAssignments([]string{"app=nginx:1.27", "*=busybox"})
+	if err != nil {
+		t.Fatalf("parseImageAssignments returned error: %v", err)
+	}
+	if images.imageFor("app") != "nginx:1.27" || images.imageFor("other") != "busybox" {
+		t.Errorf("unexpected assignments: %v", images)
+	}
+
+	for _, args := range [][]string{{"app"}, {"=nginx"}, {"app="}, {"app=a", "app=b"}} {
+		if _, err := parseImageAssignments(args); err == nil {
+			t.Errorf("%v: expected error", args)
+		}
+	}
+}
+
+func TestSetDeploymentImages(t *testing.T) {
+	clientset := fake.NewClientset(newSetImageTestDeployment())
+
+	updated, err := setDeploymentImages(context.Background(), clientset, "default", "web", imageAssignments{"app": "nginx:1.27"}, "set image deployment web app=nginx:1.27")
+	if err != nil {
+		t.Fatalf("setDeploymentImages returned error: %v", err)
+	}
+	if len(updated) != 1 || updated[0] != "app" {
+		t.Errorf("updated = %v, want [app]", updated)
+	}
+
+	got, err := clientset.AppsV1().Deployments("default").Get(context.Background(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	containers := got.Spec.Template.Spec.Containers
+	if len(containers) != 2 || containers[0].Image != "nginx:1.27" || containers[1].Image != "envoy:v1.30" {
+		t.Errorf("unexpected containers after patch: %+v", containers)
+	}
+	if len(containers[0].Ports) != 1 {
+		t.Errorf("patch dropped container fields: %+v", containers[0])
+	}
+	if got.Annotations[changeCauseAnnotation] != "set image deployment web app=nginx:1.27" {
+		t.Errorf("change-cause = %q", got.Annotations[changeCauseAnnotation])
+	}
+}
+
+func TestSetDeploymentImages_Wildcard(t *testing.T) {
+	clientset := fake.NewClientset(newSetImageTestDeployment())
+
+	updated, err := setDeploymentImages(context.Background(), clientset, "default", "web", imageAssignments{"*": "busybox", "sidecar": "envoy:v1.31"}, "")
+	if err != nil {
+		t.Fatalf("setDeploymentImages returned error: %v", err)
+	}
+	if len(updated) != 2 {
+		t.Errorf("updated = %v, want both containers", updated)
+	}
+	got, _ := clientset.AppsV1().Deployments("default").Get(context.Background(), "web", metav1.GetOptions{})
+	containers := got.Spec.Template.Spec.Containers
+	if containers[0].Image != "busybox" || containers[1].Image != "envoy:v1.31" {
+		t.Errorf("unexpected images: %s, %s", containers[0].Image, containers[1].Image)
+	}
+	if _, ok := got.Annotations[changeCauseAnnotation]; ok {
+		t.Errorf("change-cause recorded without --record")
+	}
+}
+
+func TestSetDeploymentImages_UnknownContainer(t *testing.T) {
+	clientset := fake.NewClientset(newSetImageTestDeployment())
+
+	_, err := setDeploymentImages(context.Background(), clientset, "default", "web", imageAssignments{"db": "postgres:16"}, "")
+	if err == nil {
+		t.Fatal("expected error for unknown container")
+	}
+	if !strings.Contains(err.Error(), "valid containers: app, sidecar") {
+		t.Errorf("error does not list valid containers: %v", err)
+	}
+	for _, action := range clientset.Actions() {
+		if action.GetVerb() == "patch" {
+			t.Errorf("deployment patched despite validation error")
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var triageCmd = &cobra.Command{
+	Use:   "triage",
+	Short: "Rank pods by restart count with their last crash reason",
+	Long: `Scan a namespace (or all namespaces with --all-namespaces) and list restarting pods,
+worst first, with the reason and exit code of their most recent container termination.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		allNamespaces, _ := cmd.Flags().GetBool("all-namespaces")
+		if err := triage(allNamespaces); err != nil {
+			exitWithError("Failed to triage pods", err)
+		}
+	},
+}
+
+// podTriage is one row of the triage report.
+type podTriage struct {
+	Namespace string
+	Name      string
+	Restarts  int32
+	Container string
+	Reason    string
+	ExitCode  *int32
+}
+
+func triage(allNamespaces bool) error {
+	ns := namespace
+	if allNamespaces {
+		ns = metav1.NamespaceAll
+	}
+	log.Info().Str("namespace", ns).Msg("Triaging pods")
+
+	clientset, err := getKubeClient()
+	if err != nil {
+		return err
+	}
+
+	pods, err := clientset.CoreV1().Pods(ns).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	rows := rankRestartingPods(pods.Items)
+	if len(rows) == 0 {
+		if allNamespaces {
+			fmt.Println("No restarting pods found in any namespace")
+		} else {
+			fmt.Printf("No restarting pods found in namespace '%s'\n", ns)
+		}
+		return nil
+	}
+	printTriage(os.Stdout, rows, allNamespaces)
+	return nil
+}
+
+// rankRestartingPods returns the pods that have restarted, sorted by restart count descending.
+func rankRestartingPods(pods []corev1.Pod) []podTriage {
+	var rows []podTriage
+	for _, pod := range pods {
+		restarts := getPodRestartCount(pod)
+		if restarts == 0 {
+			continue
+		}
+		row := podTriage{Namespace: pod.Namespace, Name: pod.Name, Restarts: restarts}
+		if terminated, container := getPodLastTermination(pod); terminated != nil {
+			row.Container = container
+			row.Reason = terminated.Reason
+			row.ExitCode = &terminated.ExitCode
+		}
+		rows = append(rows, row)
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Restarts != rows[j].Restarts {
+			return rows[i].Restarts > rows[j].Restarts
+		}
+		if rows[i].Namespace != rows[j].Namespace {
+			return rows[i].Namespace < rows[j].Namespace
+		}
+		return rows[i].Name < rows[j].Name
+	})
+	return rows
+}
+
+func printTriage(out io.Writer, rows []podTriage, withNamespace bool) {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	if withNamespace {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tRESTARTS\tCONTAINER\tLAST REASON\tEXIT CODE")
+	for _, row := range rows {
+		container, reason, exitCode := orNone(row.Container), orNone(row.Reason), "<none>"
+		if row.ExitCode != nil {
+			exitCode = strconv.Itoa(int(*row.ExitCode))
+		}
+		if withNamespace {
+			fmt.Fprintf(w, "%s\t", row.Namespace)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", row.Name, row.Restarts, container, reason, exitCode)
+	}
+	w.Flush()
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
+
+func init() {
+	rootCmd.AddCommand(triageCmd)
+	triageCmd.Flags().BoolP("all-namespaces", "A", false, "Scan pods in all namespaces")
+}
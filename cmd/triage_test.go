@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTriageTestPod(name string, statuses ...corev1.ContainerStatus) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Status:     corev1.PodStatus{ContainerStatuses: statuses},
+	}
+}
+
+func terminatedStatus(container string, restarts int32, reason string, exitCode int32, finished time.Time) corev1.ContainerStatus {
+	return corev1.ContainerStatus{
+		Name:         container,
+		RestartCount: restarts,
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			Reason:     reason,
+			ExitCode:   exitCode,
+			FinishedAt: metav1.NewTime(finished),
+		}},
+	}
+}
+
+func TestRankRestartingPods(t *testing.T) {
+	now := time.Now()
+	pods := []corev1.Pod{
+		newTriageTestPod("healthy", corev1.ContainerStatus{Name: "app"}),
+		newTriageTestPod("flaky", terminatedStatus("app", 2, "Error", 1, now)),
+		newTriageTestPod("crashing",
+			terminatedStatus("app", 7, "OOMKilled", 137, now.Add(-time.Minute)),
+			terminatedStatus("sidecar", 3, "Error", 2, now),
+		),
+	}
+
+	rows := rankRestartingPods(pods)
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 restarting pods, got %d", len(rows))
+	}
+	if rows[0].Name != "crashing" || rows[0].Restarts != 10 {
+		t.Errorf("expected crashing with 10 restarts first, got %s with %d", rows[0].Name, rows[0].Restarts)
+	}
+	if rows[0].Container != "sidecar" || rows[0].Reason != "Error" || *rows[0].ExitCode != 2 {
+		t.Errorf("expected most recent termination (sidecar, Error, 2), got (%s, %s, %d)", rows[0].Container, rows[0].Reason, *rows[0].ExitCode)
+	}
+	if rows[1].Name != "flaky" {
+		t.Errorf("expected flaky second, got %s", rows[1].Name)
+	}
+}
+
+func TestPrintTriage(t *testing.T) {
+	var buf bytes.Buffer
+	exitCode := int32(137)
+	printTriage(&buf, []podTriage{
+		{Namespace: "prod", Name: "api", Restarts: 4, Container: "app", Reason: "OOMKilled", ExitCode: &exitCode},
+		{Namespace: "prod", Name: "worker", Restarts: 1},
+	}, true)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and 2 rows, got:\n%s", buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "NAMESPACE") {
+		t.Errorf("expected NAMESPACE column, got %q", lines[0])
+	}
+	if fields := strings.Fields(lines[1]); strings.Join(fields, " ") != "prod api 4 app OOMKilled 137" {
+		t.Errorf("unexpected row %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "<none>") {
+		t.Errorf("expected <none> placeholders, got %q", lines[2])
+	}
+}
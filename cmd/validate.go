@@ -0,0 +1,376 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/yaml"
+)
+
+var validateOffline bool
+
+var validateCmd = &cobra.Command{
+	Use:   "validate -f FILE",
+	Short: "Validate manifests without applying them",
+	Long: "Decode every document of a manifest, check it against the known kinds and their required fields, " +
+		"and run a server-side dry-run when a cluster is reachable. Exits non-zero if any document fails",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		filename, _ := cmd.Flags().GetString("filename")
+		if filename == "" {
+			exitWithError("Failed to validate manifest", errors.New("a manifest file is required, use -f FILE"))
+		}
+		ok, err := validateFile(context.Background(), filename, os.Stdout)
+		if err != nil {
+			exitWithError("Failed to validate manifest", err)
+		}
+		if !ok {
+			os.Exit(1)
+		}
+	},
+}
+
+// manifestDocument is one YAML document of a manifest file.
+type manifestDocument struct {
+	// Index is the 1-based position of the document in the file.
+	Index int
+	// Line is the file line the document content starts on.
+	Line int
+	Data []byte
+}
+
+// validationResult is the outcome for one non-empty manifest document.
+type validationResult struct {
+	Document manifestDocument
+	Objects  []*unstructured.Unstructured
+	Err      error
+}
+
+// serverValidator checks objects against the API server. A nil serverValidator
+// validates offline.
+type serverValidator struct {
+	client dynamic.Interface
+	mapper meta.RESTMapper
+}
+
+func validateFile(ctx context.Context, path string, out io.Writer) (bool, error) {
+	log.Info().Str("file", path).Bool("offline", validateOffline).Msg("Validating manifest")
+
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return false, fmt.Errorf("failed to open manifest: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+	docs, err := splitManifestDocuments(r)
+	if err != nil {
+		return false, err
+	}
+
+	var server *serverValidator
+	if !validateOffline {
+		if server, err = newServerValidator(); err != nil {
+			fmt.Fprintf(os.Stderr, "Cluster not reachable, skipping server-side validation: %v\n", err)
+		}
+	}
+
+	results := validateDocuments(ctx, docs, server)
+	printValidationResults(out, results)
+	for _, r := range results {
+		if r.Err != nil {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// newServerValidator connects to the cluster from the kubeconfig and checks that it answers.
+func newServerValidator() (*serverValidator, error) {
+	config, err := getKubeConfig()
+	if err != nil {
+		return nil, err
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	if _, err := discoveryClient.ServerVersion(); err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+	return &serverValidator{client: dynamicClient, mapper: mapper}, nil
+}
+
+// splitManifestDocuments splits a multi-document YAML stream on "---" lines, keeping the
+// line each document starts on so errors can point at the file.
+func splitManifestDocuments(r io.Reader) ([]manifestDocument, error) {
+	var docs []manifestDocument
+	current := manifestDocument{Index: 1, Line: 1}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if isDocumentSeparator(text) {
+			docs = append(docs, current)
+			current = manifestDocument{Index: current.Index + 1, Line: line + 1}
+			continue
+		}
+		current.Data = append(current.Data, text...)
+		current.Data = append(current.Data, '\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	return append(docs, current), nil
+}
+
+func isDocumentSeparator(line string) bool {
+	rest, ok := strings.CutPrefix(line, "---")
+	return ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t')
+}
+
+// validateDocuments validates every non-empty document, also against the server when set.
+func validateDocuments(ctx context.Context, docs []manifestDocument, server *serverValidator) []validationResult {
+	var results []validationResult
+	for _, doc := range docs {
+		objs, empty, err := decodeManifestDocument(doc)
+		if empty {
+			continue
+		}
+		result := validationResult{Document: doc, Objects: objs, Err: err}
+		if err == nil {
+			var errs []error
+			for _, obj := range objs {
+				if err := validateObject(ctx, obj, server); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			result.Err = errors.Join(errs...)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// decodeManifestDocument parses one document, expanding List kinds. Documents holding
+// only comments or whitespace are reported as empty.
+func decodeManifestDocument(doc manifestDocument) ([]*unstructured.Unstructured, bool, error) {
+	jsonData, err := yaml.YAMLToJSON(doc.Data)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid YAML: %w", shiftYAMLLines(err, doc.Line))
+	}
+	trimmed := bytes.TrimSpace(jsonData)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return nil, true, nil
+	}
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(jsonData); err != nil {
+		return nil, false, err
+	}
+	if !obj.IsList() {
+		return []*unstructured.Unstructured{obj}, false, nil
+	}
+	var objs []*unstructured.Unstructured
+	err = obj.EachListItem(func(item runtime.Object) error {
+		objs = append(objs, item.(*unstructured.Unstructured))
+		return nil
+	})
+	return objs, false, err
+}
+
+var yamlLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// shiftYAMLLines rewrites the document-relative line numbers in a YAML parse error to
+// file lines.
+func shiftYAMLLines(err error, firstLine int) error {
+	msg := yamlLinePattern.ReplaceAllStringFunc(err.Error(), func(m string) string {
+		n, _ := strconv.Atoi(strings.TrimPrefix(m, "line "))
+		return "line " + strconv.Itoa(firstLine+n-1)
+	})
+	return errors.New(msg)
+}
+
+// validateObject checks that obj has a name and a known kind, that a built-in kind has no
+// unknown fields and sets its required fields, and finally asks the server, if any.
+func validateObject(ctx context.Context, obj *unstructured.Unstructured, server *serverValidator) error {
+	gvk := obj.GroupVersionKind()
+	if obj.GetAPIVersion() == "" {
+		return fmt.Errorf("%s: apiVersion is required", gvk.Kind)
+	}
+	if obj.GetName() == "" && obj.GetGenerateName() == "" {
+		return fmt.Errorf("%s: metadata.name is required", gvk.Kind)
+	}
+
+	if scheme.Scheme.Recognizes(gvk) {
+		typed, err := scheme.Scheme.New(gvk)
+		if err != nil {
+			return err
+		}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructuredWithValidation(obj.Object, typed, true); err != nil {
+			return fmt.Errorf("%s: %w", objectDescription(obj), err)
+		}
+		if err := checkRequiredFields(typed); err != nil {
+			return fmt.Errorf("%s: %w", objectDescription(obj), err)
+		}
+	} else if server == nil || !server.knows(gvk) {
+		return fmt.Errorf("%s: unknown kind %q for apiVersion %q", objectDescription(obj), gvk.Kind, obj.GetAPIVersion())
+	}
+
+	if server != nil {
+		if err := server.dryRun(ctx, obj); err != nil {
+			return fmt.Errorf("%s: server rejected object: %w", objectDescription(obj), err)
+		}
+	}
+	return nil
+}
+
+func objectDescription(obj *unstructured.Unstructured) string {
+	name := obj.GetName()
+	if name == "" {
+		name = obj.GetGenerateName()
+	}
+	return fmt.Sprintf("%s '%s'", obj.GetKind(), name)
+}
+
+// checkRequiredFields reports required fields missing from built-in workloads and services.
+func checkRequiredFields(obj runtime.Object) error {
+	var errs []error
+	switch o := obj.(type) {
+	case *corev1.Pod:
+		errs = append(errs, checkPodSpec("spec", &o.Spec)...)
+	case *appsv1.Deployment:
+		errs = append(errs, checkWorkload(o.Spec.Selector, &o.Spec.Template)...)
+	case *appsv1.StatefulSet:
+		errs = append(errs, checkWorkload(o.Spec.Selector, &o.Spec.Template)...)
+	case *appsv1.DaemonSet:
+		errs = append(errs, checkWorkload(o.Spec.Selector, &o.Spec.Template)...)
+	case *appsv1.ReplicaSet:
+		errs = append(errs, checkWorkload(o.Spec.Selector, &o.Spec.Template)...)
+	case *batchv1.Job:
+		errs = append(errs, checkPodSpec("spec.template.spec", &o.Spec.Template.Spec)...)
+	case *corev1.Service:
+		for i, port := range o.Spec.Ports {
+			if port.Port == 0 {
+				errs = append(errs, fmt.Errorf("spec.ports[%d].port is required", i))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func checkWorkload(selector *metav1.LabelSelector, template *corev1.PodTemplateSpec) []error {
+	var errs []error
+	if selector == nil {
+		errs = append(errs, errors.New("spec.selector is required"))
+	} else if s, err := metav1.LabelSelectorAsSelector(selector); err != nil {
+		errs = append(errs, fmt.Errorf("spec.selector is invalid: %w", err))
+	} else if !s.Matches(labels.Set(template.Labels)) {
+		errs = append(errs, errors.New("spec.selector does not match spec.template.metadata.labels"))
+	}
+	return append(errs, checkPodSpec("spec.template.spec", &template.Spec)...)
+}
+
+func checkPodSpec(path string, spec *corev1.PodSpec) []error {
+	if len(spec.Containers) == 0 {
+		return []error{fmt.Errorf("%s.containers must not be empty", path)}
+	}
+	var errs []error
+	for i, c := range spec.Containers {
+		if c.Name == "" {
+			errs = append(errs, fmt.Errorf("%s.containers[%d].name is required", path, i))
+		}
+		if c.Image == "" {
+			errs = append(errs, fmt.Errorf("%s.containers[%d].image is required", path, i))
+		}
+	}
+	return errs
+}
+
+// knows reports whether the server serves gvk, e.g. a custom resource.
+func (s *serverValidator) knows(gvk schema.GroupVersionKind) bool {
+	_, err := s.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	return err == nil
+}
+
+// dryRun submits obj with dryRun=All, so admission and server-side validation run but
+// nothing is persisted. Named objects are applied, which also works when they already
+// exist; objects with only generateName are created. Objects without a namespace fall
+// back to the --namespace flag.
+func (s *serverValidator) dryRun(ctx context.Context, obj *unstructured.Unstructured) error {
+	gvk := obj.GroupVersionKind()
+	mapping, err := s.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return fmt.Errorf("failed to resolve resource: %w", err)
+	}
+	var resource dynamic.ResourceInterface = s.client.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		ns := obj.GetNamespace()
+		if ns == "" {
+			ns = namespace
+		}
+		resource = s.client.Resource(mapping.Resource).Namespace(ns)
+	}
+
+	dryRun := []string{metav1.DryRunAll}
+	if obj.GetName() == "" {
+		_, err = resource.Create(ctx, obj, metav1.CreateOptions{DryRun: dryRun, FieldManager: defaultFieldManager})
+		return err
+	}
+	_, err = resource.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{DryRun: dryRun, FieldManager: defaultFieldManager, Force: true})
+	return err
+}
+
+func printValidationResults(out io.Writer, results []validationResult) {
+	passed := 0
+	for _, r := range results {
+		location := fmt.Sprintf("document %d (line %d)", r.Document.Index, r.Document.Line)
+		if r.Err != nil {
+			fmt.Fprintf(out, "[FAIL] %s: %s\n", location, strings.ReplaceAll(r.Err.Error(), "\n", "; "))
+			continue
+		}
+		passed++
+		names := make([]string, 0, len(r.Objects))
+		for _, obj := range r.Objects {
+			names = append(names, objectDescription(obj))
+		}
+		fmt.Fprintf(out, "[PASS] %s: %s\n", location, strings.Join(names, ", "))
+	}
+	fmt.Fprintf(out, "\n%d/%d documents valid\n", passed, len(results))
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().StringP("filename", "f", "", "Manifest file to validate ('-' reads from stdin)")
+	validateCmd.Flags().BoolVar(&validateOffline, "offline", false, "Skip the server-side dry-run and validate against built-in kinds only")
+}
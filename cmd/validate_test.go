@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const validateTestManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx
+---
+# comment-only document
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  prots:
+  - port: 80
+---
+apiVersion: v1
+kind: Sevrice
+metadata:
+  name: typo
+--- # trailing comment
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: [broken
+`
+
+func TestSplitManifestDocuments(t *testing.T) {
+	docs, err := splitManifestDocuments(strings.NewReader(validateTestManifest))
+	if err != nil {
+		t.Fatalf("splitManifestDocuments returned error: %v", err)
+	}
+	wantLines := []int{1, 18, 20, 28, 33}
+	if len(docs) != len(wantLines) {
+		t.Fatalf("got %d documents, want %d", len(docs), len(wantLines))
+	}
+	for i, doc := range docs {
+		if doc.Index != i+1 || doc.Line != wantLines[i] {
+			t.Errorf("document %d: index %d line %d, want index %d line %d", i, doc.Index, doc.Line, i+1, wantLines[i])
+		}
+	}
+}
+
+func TestValidateDocuments_Offline(t *testing.T) {
+	docs, err := splitManifestDocuments(strings.NewReader(validateTestManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := validateDocuments(context.Background(), docs, nil)
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4 (the comment-only document is skipped)", len(results))
+	}
+	if results[0].Err != nil {
+		t.Errorf("deployment: unexpected error: %v", results[0].Err)
+	}
+	for i, want := range []string{`unknown field "spec.prots"`, `unknown kind "Sevrice"`, "line 36"} {
+		r := results[i+1]
+		if r.Err == nil || !strings.Contains(r.Err.Error(), want) {
+			t.Errorf("document %d: error = %v, want it to contain %q", r.Document.Index, r.Err, want)
+		}
+	}
+
+	var out bytes.Buffer
+	printValidationResults(&out, results)
+	got := out.String()
+	for _, want := range []string{"[PASS] document 1 (line 1): Deployment 'web'", "[FAIL] document 3 (line 20)", "1/4 documents valid"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("report missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestCheckRequiredFields(t *testing.T) {
+	dep := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "api"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web"}}},
+		},
+	}}
+	err := checkRequiredFields(dep)
+	if err == nil {
+		t.Fatal("expected errors for a mismatched selector and a missing image")
+	}
+	for _, want := range []string{"spec.selector does not match", "spec.template.spec.containers[0].image is required"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+
+	if err := checkRequiredFields(&corev1.Pod{}); err == nil || !strings.Contains(err.Error(), "spec.containers must not be empty") {
+		t.Errorf("pod without containers: error = %v", err)
+	}
+	if err := checkRequiredFields(&corev1.ConfigMap{}); err != nil {
+		t.Errorf("configmap: unexpected error: %v", err)
+	}
+}
+
+func TestValidateObject_ServerDryRun(t *testing.T) {
+	originalNamespace := namespace
+	defer func() { namespace = originalNamespace }()
+	namespace = "staging"
+
+	client := dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
+	var applies []k8stesting.PatchActionImpl
+	client.PrependReactor("patch", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		applies = append(applies, action.(k8stesting.PatchActionImpl))
+		return true, nil, nil
+	})
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+	server := &serverValidator{client: client, mapper: mapper}
+
+	docs, err := splitManifestDocuments(strings.NewReader(validateTestManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := validateDocuments(context.Background(), docs[:1], server)
+	if results[0].Err != nil {
+		t.Fatalf("unexpected error: %v", results[0].Err)
+	}
+	if len(applies) != 1 {
+		t.Fatalf("got %d apply requests, want 1", len(applies))
+	}
+	if applies[0].GetPatchType() != types.ApplyPatchType || applies[0].GetNamespace() != "staging" {
+		t.Errorf("got %s patch in namespace %q, want an apply in the --namespace namespace", applies[0].GetPatchType(), applies[0].GetNamespace())
+	}
+}
+
+func TestValidateObject_ServerKnowsCustomKind(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}, meta.RESTScopeNamespace)
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), nil)
+	client.PrependReactor("patch", "widgets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, nil
+	})
+	server := &serverValidator{client: client, mapper: mapper}
+
+	docs, err := splitManifestDocuments(strings.NewReader("apiVersion: example.com/v1\nkind: Widget\nmetadata:\n  name: w\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := validateDocuments(context.Background(), docs, nil); r[0].Err == nil {
+		t.Error("expected an unknown kind error offline")
+	}
+	if r := validateDocuments(context.Background(), docs, server); r[0].Err != nil {
+		t.Errorf("kind served by the cluster was rejected: %v", r[0].Err)
+	}
+}
+
+func TestValidateFile_Offline(t *testing.T) {
+	originalOffline := validateOffline
+	defer func() { validateOffline = originalOffline }()
+	validateOffline = true
+
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	if err := os.WriteFile(path, []byte(strings.SplitN(validateTestManifest, "---", 2)[0]), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	ok, err := validateFile(context.Background(), path, &out)
+	if err != nil || !ok {
+		t.Errorf("validateFile = %v, %v, want a passing manifest:\n%s", ok, err, out.String())
+	}
+
+	if err := os.WriteFile(path, []byte(validateTestManifest), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if ok, err := validateFile(context.Background(), path, &out); err != nil || ok {
+		t.Errorf("validateFile = %v, %v, want failing documents to be reported", ok, err)
+	}
+}
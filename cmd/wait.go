@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/deploystatus"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+var waitCmd = &cobra.Command{
+	Use:   "wait",
+	Short: "Wait for Kubernetes resources to reach a condition",
+}
+
+var waitDeploymentCmd = &cobra.Command{
+	Use:     "deployment [name]",
+	Short:   "Wait until a deployment is available, updated or complete",
+	Aliases: []string{"deploy"},
+	Long: `Wait until a deployment reaches the condition given with --for:
+  available            available replicas reach the desired count (default)
+  updated              updated replicas reach the desired count
+  complete             the rollout is done and the latest generation has been observed
+  condition=TYPE       the status condition TYPE (e.g. Available, Progressing) is True`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		forValue, _ := cmd.Flags().GetString("for")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		cond, err := deploystatus.ParseCondition(forValue)
+		if err != nil {
+			exitWithError("Invalid --for condition", err)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		clientset, err := getKubeClient()
+		if err != nil {
+			exitWithError("Failed to create Kubernetes client", err)
+		}
+		if err := waitForDeployment(ctx, clientset, namespace, args[0], cond, os.Stdout); err != nil {
+			exitWithError("Wait did not complete", err)
+		}
+	},
+}
+
+// waitForDeployment blocks until the deployment meets cond or ctx is done.
+func waitForDeployment(ctx context.Context, clientset kubernetes.Interface, ns, name string, cond deploystatus.Condition, out io.Writer) error {
+	log.Info().Str("name", name).Str("namespace", ns).Str("for", cond.String()).Msg("Waiting for deployment")
+
+	_, err := watchDeployment(ctx, clientset, ns, name, func(event watch.Event) (bool, error) {
+		switch event.Type {
+		case watch.Deleted:
+			return false, apierrors.NewNotFound(appsv1.Resource("deployments"), name)
+		case watch.Added, watch.Modified:
+			deployment, ok := event.Object.(*appsv1.Deployment)
+			if !ok {
+				return false, fmt.Errorf("unexpected object type %T", event.Object)
+			}
+			return cond.Met(deployment), nil
+		}
+		return false, nil
+	})
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out waiting for deployment %q to be %s: %w", name, cond, context.DeadlineExceeded)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Deployment '%s' is %s in namespace '%s'\n", name, cond, ns)
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(waitCmd)
+	waitCmd.AddCommand(waitDeploymentCmd)
+
+	waitDeploymentCmd.Flags().String("for", "available", "Condition to wait for: available, updated, complete or condition=TYPE")
+	waitDeploymentCmd.Flags().Duration("timeout", 0, "Give up waiting after this long, e.g. 5m (0 waits forever)")
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/deploystatus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestWaitForDeployment_AlreadyMet(t *testing.T) {
+	// Available on the old version: 3 available, only 1 updated.
+	clientset := fake.NewClientset(newRolloutTestDeployment(3, 1, 3))
+
+	var out bytes.Buffer
+	if err := waitForDeployment(context.Background(), clientset, "default", "web", deploystatus.Available, &out); err != nil {
+		t.Fatalf("waitForDeployment returned error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Deployment 'web' is available") {
+		t.Errorf("unexpected output: %q", out.String())
+	}
+}
+
+func TestWaitForDeployment_Timeout(t *testing.T) {
+	clientset := fake.NewClientset(newRolloutTestDeployment(3, 1, 3))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := waitForDeployment(ctx, clientset, "default", "web", deploystatus.Complete, &bytes.Buffer{})
+	if exitCodeFor(err) != exitTimeout {
+		t.Errorf("expected timeout error, got %v", err)
+	}
+}
+
+func TestWaitForDeployment_ConditionBecomesTrue(t *testing.T) {
+	clientset := fake.NewClientset(newRolloutTestDeployment(3, 3, 3))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- waitForDeployment(ctx, clientset, "default", "web", deploystatus.HasCondition(appsv1.DeploymentAvailable), &bytes.Buffer{})
+	}()
+
+	// Update the status only once the watch is open, so the change is not missed.
+	for !hasWatchAction(clientset) {
+		select {
+		case err := <-done:
+			t.Fatalf("waitForDeployment returned early: %v", err)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	available := newRolloutTestDeployment(3, 3, 3)
+	available.Status.Conditions = []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}}
+	if _, err := clientset.AppsV1().Deployments("default").UpdateStatus(ctx, available, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("waitForDeployment returned error: %v", err)
+	}
+}
+
+func TestWaitForDeployment_NotFound(t *testing.T) {
+	err := waitForDeployment(context.Background(), fake.NewClientset(), "default", "missing", deploystatus.Available, &bytes.Buffer{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func hasWatchAction(clientset *fake.Clientset) bool {
+	for _, action := range clientset.Actions() {
+		if action.GetVerb() == "watch" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/informer"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+var watchNameFilter string
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Print Kubernetes resource events as they happen",
+}
+
+var watchDeploymentsCmd = &cobra.Command{
+	Use:     "deployments",
+	Short:   "Print deployment adds, updates and deletes until interrupted",
+	Long:    "Start a deployment informer and print one line per event until Ctrl-C, optionally only for deployments whose name matches --name-filter",
+	Aliases: []string{"deployment", "deploy"},
+	Args:    cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		clientset, err := getKubeClient()
+		if err != nil {
+			exitWithError("Failed to create Kubernetes client", err)
+		}
+		ns := namespace
+		if allNamespaces {
+			ns = metav1.NamespaceAll
+		}
+		if err := tailDeployments(ctx, clientset, ns, watchNameFilter, os.Stdout); err != nil {
+			exitWithError("Failed to watch deployments", err)
+		}
+	},
+}
+
+// tailDeployments prints the events of deployments in ns whose name matches the glob
+// pattern until ctx is done. Deployments that already exist are printed as ADDED first.
+func tailDeployments(ctx context.Context, clientset kubernetes.Interface, ns, pattern string, out io.Writer) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid --name-filter %q: %w", pattern, err)
+	}
+	log.Info().Str("namespace", ns).Str("name_filter", pattern).Msg("Watching deployments")
+
+	di, err := informer.NewDeploymentInformer(informer.InformerConfig{Clientset: clientset, Namespace: ns})
+	if err != nil {
+		return err
+	}
+	if _, err := di.AddEventHandler(deploymentEventPrinter(out, pattern)); err != nil {
+		return err
+	}
+	di.Start(ctx)
+	defer di.Stop()
+	if !di.WaitForCacheSync(ctx) {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return errors.New("deployment informer failed to sync")
+	}
+	<-ctx.Done()
+	return nil
+}
+
+// deploymentEventPrinter returns a handler that writes one line per event for deployments
+// whose name matches pattern. An empty pattern matches every deployment. Resyncs, which
+// carry no change, are not printed.
+func deploymentEventPrinter(out io.Writer, pattern string) cache.ResourceEventHandler {
+	printEvent := func(eventType string, obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		dep, ok := obj.(*appsv1.Deployment)
+		if !ok {
+			return
+		}
+		if pattern != "" {
+			if matched, _ := path.Match(pattern, dep.Name); !matched {
+				return
+			}
+		}
+		replicas := int32(1)
+		if dep.Spec.Replicas != nil {
+			replicas = *dep.Spec.Replicas
+		}
+		fmt.Fprintf(out, "%s  %-8s %s/%s  %d/%d ready\n",
+			time.Now().Format("15:04:05"), eventType, dep.Namespace, dep.Name, dep.Status.ReadyReplicas, replicas)
+	}
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			printEvent(informer.WebhookEventAdded, obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldDep, okOld := oldObj.(*appsv1.Deployment)
+			newDep, okNew := newObj.(*appsv1.Deployment)
+			if okOld && okNew && oldDep.ResourceVersion == newDep.ResourceVersion {
+				return
+			}
+			printEvent(informer.WebhookEventUpdated, newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			printEvent(informer.WebhookEventDeleted, obj)
+		},
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.AddCommand(watchDeploymentsCmd)
+	watchDeploymentsCmd.Flags().StringVar(&watchNameFilter, "name-filter", "", "Only print deployments whose name matches this glob, e.g. 'web-*'")
+	watchCmd.PersistentFlags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "Watch all namespaces (kube-system, kube-public and kube-node-lease are ignored)")
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestTailDeployments(t *testing.T) {
+	clientset := fake.NewClientset(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Namespace: "default"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"}},
+	)
+	var out lockedBuffer
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- tailDeployments(ctx, clientset, "default", "web-*", &out) }()
+
+	waitFor := func(what string, cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s, output:\n%s", what, out.String())
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	waitFor("the watch", func() bool { return hasWatchAction(clientset) })
+
+	deployments := clientset.AppsV1().Deployments("default")
+	created, err := deployments.Create(ctx, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web-2", Namespace: "default"}}, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created.Status.ReadyReplicas = 1
+	created.ResourceVersion = "2"
+	if _, err := deployments.UpdateStatus(ctx, created, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := deployments.Delete(ctx, "api", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := deployments.Delete(ctx, "web-1", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("the delete of web-1", func() bool { return strings.Contains(out.String(), "DELETED  default/web-1") })
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("tailDeployments returned error: %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{"ADDED    default/web-1  0/1 ready", "ADDED    default/web-2", "UPDATED  default/web-2  1/1 ready"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "default/api") {
+		t.Errorf("deployment not matching the filter was printed:\n%s", got)
+	}
+}
+
+func TestTailDeployments_InvalidFilter(t *testing.T) {
+	var out lockedBuffer
+	err := tailDeployments(context.Background(), fake.NewClientset(), "default", "web-[", &out)
+	if err == nil || !strings.Contains(err.Error(), "--name-filter") {
+		t.Errorf("expected an invalid filter error, got %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package ctrl
+
+import (
+	context "context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestDebouncedHandler_CoalescesUpdates(t *testing.T) {
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+	h := debouncedHandler(200 * time.Millisecond)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", ResourceVersion: strconv.Itoa(i + 1)}}
+		h.Update(ctx, event.UpdateEvent{ObjectOld: dep, ObjectNew: dep}, q)
+	}
+	require.Equal(t, 0, q.Len(), "updates should wait for the debounce window")
+
+	require.Eventually(t, func() bool { return q.Len() == 1 }, 2*time.Second, 10*time.Millisecond)
+	item, _ := q.Get()
+	require.Equal(t, "default/web", item.String())
+	q.Done(item)
+
+	time.Sleep(300 * time.Millisecond)
+	require.Equal(t, 0, q.Len(), "coalesced updates should be reconciled only once")
+}
+
+func TestDebouncedHandler_CreateAndDeleteAreImmediate(t *testing.T) {
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+	h := debouncedHandler(time.Hour)
+	ctx := context.Background()
+
+	h.Create(ctx, event.CreateEvent{Object: &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}}, q)
+	h.Delete(ctx, event.DeleteEvent{Object: &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"}}}, q)
+	require.Equal(t, 2, q.Len())
+}
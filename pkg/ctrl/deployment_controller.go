@@ -2,8 +2,16 @@ package ctrl
 
 import (
 	context "context"
+	"fmt"
+	"io"
+	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -13,12 +21,52 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
+// Options configures the deployment controller.
+type Options struct {
+	// DryRun logs the writes Reconcile would make instead of sending them to the API server.
+	DryRun bool
+	// ConsoleEvents, when set, receives a short human-readable line per reconcile, such as
+	// "reconciled deployment default/web: 3/3 ready", in addition to the structured logs.
+	ConsoleEvents io.Writer
+	// ReconcileDebounce delays reconciles triggered by updates by this long, coalescing all
+	// updates to the same deployment within the window into one. Zero reconciles each update.
+	ReconcileDebounce time.Duration
+	// StatusConfigMap, when its Name is set, is a ConfigMap that Reconcile keeps up to date
+	// with the ready and desired replicas of every reconciled deployment, keyed by
+	// "<namespace>.<name>". It is read and written without the manager's cache.
+	StatusConfigMap types.NamespacedName
+	// FieldManager is recorded in managedFields for the controller's writes, such as the
+	// status ConfigMap. Empty leaves the API server's default.
+	FieldManager string
+}
+
+// PausedAnnotation set to "true" on a deployment makes Reconcile skip it until the
+// annotation is removed, so operators can intervene by hand.
+const PausedAnnotation = "k8s-controller/paused"
+
+// tracer is a no-op until a tracer provider is installed with tracing.Setup.
+var tracer = otel.Tracer("github.com/yourusername/k8s-controller-tutorial/pkg/ctrl")
+
 type DeploymentReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// Console, when set, gets one concise line per reconcile for people watching a terminal.
+	Console *zerolog.Logger
+	// StatusConfigMap is the health ConfigMap; an empty Name disables it.
+	StatusConfigMap types.NamespacedName
+	// StatusClient, when set, is used for the status ConfigMap instead of Client.
+	StatusClient client.Client
+	// FieldManager, when set, owns the fields written to the status ConfigMap.
+	FieldManager string
 }
 
 func (r *DeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracer.Start(ctx, "DeploymentReconciler.Reconcile", trace.WithAttributes(
+		attribute.String("k8s.namespace.name", req.Namespace),
+		attribute.String("k8s.deployment.name", req.Name),
+	))
+	defer span.End()
+
 	log.Info().Msgf("Reconciling Deployment: %s/%s", req.Namespace, req.Name)
 
 	// Fetch the deployment
@@ -31,18 +79,60 @@ func (r *DeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	if err != nil {
 		if client.IgnoreNotFound(err) == nil {
 			log.Info().Msgf("Deployment %s/%s was deleted", req.Namespace, req.Name)
+			r.consoleEvent("deleted deployment %s/%s", req.Namespace, req.Name)
+			if r.StatusConfigMap.Name != "" {
+				if err := r.forgetStatus(ctx, req.NamespacedName); err != nil {
+					log.Error().Err(err).Msgf("Failed to remove Deployment %s/%s from ConfigMap %s", req.Namespace, req.Name, r.StatusConfigMap)
+					span.RecordError(err)
+					span.SetStatus(codes.Error, "failed to update status configmap")
+					return ctrl.Result{}, err
+				}
+			}
 			return ctrl.Result{}, nil
 		}
 		log.Error().Err(err).Msgf("Failed to fetch Deployment %s/%s", req.Namespace, req.Name)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to fetch deployment")
 		return ctrl.Result{}, err
 	}
 
+	if isPaused(deployment) {
+		log.Info().Msgf("Deployment %s/%s is paused by the %s annotation, skipping", req.Namespace, req.Name, PausedAnnotation)
+		span.SetAttributes(attribute.Bool("k8s_controller.paused", true))
+		return ctrl.Result{}, nil
+	}
+
 	// Log deployment details
 	r.logDeploymentEvent(deployment)
 
+	desired := int32(1)
+	if deployment.Spec.Replicas != nil {
+		desired = *deployment.Spec.Replicas
+	}
+	r.consoleEvent("reconciled deployment %s/%s: %d/%d ready", req.Namespace, req.Name, deployment.Status.ReadyReplicas, desired)
+
+	if r.StatusConfigMap.Name != "" {
+		if err := r.recordStatus(ctx, deployment, desired); err != nil {
+			log.Error().Err(err).Msgf("Failed to record Deployment %s/%s in ConfigMap %s", req.Namespace, req.Name, r.StatusConfigMap)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to update status configmap")
+			return ctrl.Result{}, err
+		}
+	}
+
 	return ctrl.Result{}, nil
 }
 
+func (r *DeploymentReconciler) consoleEvent(format string, args ...any) {
+	if r.Console != nil {
+		r.Console.Info().Msgf(format, args...)
+	}
+}
+
+func isPaused(deployment *appsv1.Deployment) bool {
+	return deployment.Annotations[PausedAnnotation] == "true"
+}
+
 func (r *DeploymentReconciler) logDeploymentEvent(deployment *appsv1.Deployment) {
 	name := deployment.Name
 	namespace := deployment.Namespace
@@ -117,13 +207,46 @@ func (r *DeploymentReconciler) logDeploymentEvent(deployment *appsv1.Deployment)
 	}
 }
 
-func AddDeploymentController(mgr manager.Manager) error {
+// newConsoleLogger returns a logger that pretty-prints to w for the ConsoleEvents option.
+func newConsoleLogger(w io.Writer) *zerolog.Logger {
+	console := zerolog.New(zerolog.ConsoleWriter{Out: w, TimeFormat: "15:04:05"}).With().Timestamp().Logger()
+	return &console
+}
+
+func AddDeploymentController(mgr manager.Manager, opts Options) error {
+	c := mgr.GetClient()
+	if opts.DryRun {
+		log.Info().Msg("Deployment controller running in dry-run mode, writes will only be logged")
+		c = newDryRunClient(c)
+	}
 	r := &DeploymentReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:          c,
+		Scheme:          mgr.GetScheme(),
+		StatusConfigMap: opts.StatusConfigMap,
+		FieldManager:    opts.FieldManager,
+	}
+	if opts.StatusConfigMap.Name != "" {
+		// A cached client would watch ConfigMaps in every namespace to serve one object.
+		statusClient, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+		if err != nil {
+			return fmt.Errorf("failed to create status ConfigMap client: %w", err)
+		}
+		if opts.DryRun {
+			statusClient = newDryRunClient(statusClient)
+		}
+		r.StatusClient = statusClient
+		log.Info().Str("configmap", opts.StatusConfigMap.String()).Msg("Recording deployment health in ConfigMap")
+	}
+	if opts.ConsoleEvents != nil {
+		r.Console = newConsoleLogger(opts.ConsoleEvents)
+	}
+	b := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{MaxConcurrentReconciles: 1})
+	if opts.ReconcileDebounce > 0 {
+		log.Info().Dur("window", opts.ReconcileDebounce).Msg("Debouncing deployment update reconciles")
+		b = b.Named("deployment").Watches(&appsv1.Deployment{}, debouncedHandler(opts.ReconcileDebounce))
+	} else {
+		b = b.For(&appsv1.Deployment{})
 	}
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&appsv1.Deployment{}).
-		WithOptions(controller.Options{MaxConcurrentReconciles: 1}).
-		Complete(r)
+	return b.Complete(r)
 }
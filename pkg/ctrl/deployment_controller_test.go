@@ -1,16 +1,23 @@
 package ctrl
 
 import (
+	"bytes"
 	context "context"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/require"
 	testutil "github.com/yourusername/k8s-controller-tutorial/pkg/testutil"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestDeploymentReconciler_BasicFlow(t *testing.T) {
@@ -18,7 +25,7 @@ func TestDeploymentReconciler_BasicFlow(t *testing.T) {
 	defer cleanup()
 
 	// Register the controller before starting the manager
-	err := AddDeploymentController(mgr)
+	err := AddDeploymentController(mgr, Options{})
 	require.NoError(t, err)
 
 	go func() {
@@ -58,4 +65,106 @@ func TestDeploymentReconciler_BasicFlow(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// logBuffer collects log output written from reconcile goroutines.
+type logBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// take returns the output so far and clears the buffer.
+func (b *logBuffer) take() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := b.buf.String()
+	b.buf.Reset()
+	return out
+}
+
+func TestDeploymentReconciler_Paused(t *testing.T) {
+	mgr, _, restCfg, cleanup := testutil.StartTestManager(t)
+	defer cleanup()
+
+	// Read straight from the API server so annotation changes are seen immediately.
+	c, err := client.New(restCfg, client.Options{Scheme: mgr.GetScheme()})
+	require.NoError(t, err)
+
+	var logs logBuffer
+	originalLogger := log.Logger
+	log.Logger = zerolog.New(&logs)
+	t.Cleanup(func() { log.Logger = originalLogger })
+
+	ctx := context.Background()
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "paused-deployment",
+			Namespace:   "default",
+			Annotations: map[string]string{PausedAnnotation: "true"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: int32Ptr(1),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "paused"}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "paused"}},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}}},
+			},
+		},
+	}
+	require.NoError(t, c.Create(ctx, dep))
+
+	r := &DeploymentReconciler{Client: c, Scheme: mgr.GetScheme()}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: dep.Namespace, Name: dep.Name}}
+
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	out := logs.take()
+	require.Contains(t, out, "is paused")
+	require.NotContains(t, out, "Deployment status", "paused deployment was reconciled")
+
+	// Removing the annotation resumes reconciliation.
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(dep), dep))
+	delete(dep.Annotations, PausedAnnotation)
+	require.NoError(t, c.Update(ctx, dep))
+
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	out = logs.take()
+	require.Contains(t, out, "Deployment status")
+	require.NotContains(t, out, "is paused")
+}
+
+func TestDeploymentReconciler_ConsoleEvents(t *testing.T) {
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(3)},
+		Status:     appsv1.DeploymentStatus{ReadyReplicas: 2},
+	}
+	var out bytes.Buffer
+	r := &DeploymentReconciler{Client: fake.NewClientBuilder().WithObjects(dep).Build(), Console: newConsoleLogger(&out)}
+
+	ctx := context.Background()
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "web"}})
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "reconciled deployment default/web: 2/3 ready")
+
+	out.Reset()
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "gone"}})
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "deleted deployment default/gone")
+}
+
+func TestIsPaused(t *testing.T) {
+	dep := &appsv1.Deployment{}
+	require.False(t, isPaused(dep))
+	dep.Annotations = map[string]string{PausedAnnotation: "false"}
+	require.False(t, isPaused(dep))
+	dep.Annotations[PausedAnnotation] = "true"
+	require.True(t, isPaused(dep))
+}
+
 func int32Ptr(i int32) *int32 { return &i }
\ No newline at end of file
@@ -0,0 +1,94 @@
+package ctrl
+
+import (
+	context "context"
+
+	"github.com/rs/zerolog/log"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// dryRunClient passes reads through to the wrapped client and replaces every
+// write with a log line describing the change that would have been made.
+type dryRunClient struct {
+	client.Client
+}
+
+func newDryRunClient(c client.Client) client.Client {
+	return dryRunClient{Client: c}
+}
+
+func (c dryRunClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	c.logWrite("create", "", obj, nil)
+	return nil
+}
+
+func (c dryRunClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	c.logWrite("update", "", obj, nil)
+	return nil
+}
+
+func (c dryRunClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	c.logWrite("patch", "", obj, patch)
+	return nil
+}
+
+func (c dryRunClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	c.logWrite("delete", "", obj, nil)
+	return nil
+}
+
+func (c dryRunClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	c.logWrite("delete-all-of", "", obj, nil)
+	return nil
+}
+
+func (c dryRunClient) Status() client.SubResourceWriter {
+	return c.SubResource("status")
+}
+
+func (c dryRunClient) SubResource(subResource string) client.SubResourceClient {
+	return dryRunSubResourceClient{SubResourceClient: c.Client.SubResource(subResource), parent: c, subResource: subResource}
+}
+
+func (c dryRunClient) logWrite(verb, subResource string, obj client.Object, patch client.Patch) {
+	event := log.Info().
+		Str("verb", verb).
+		Str("namespace", obj.GetNamespace()).
+		Str("name", obj.GetName())
+	if gvk, err := c.GroupVersionKindFor(obj); err == nil {
+		event = event.Str("kind", gvk.Kind)
+	}
+	if subResource != "" {
+		event = event.Str("subresource", subResource)
+	}
+	if patch != nil {
+		if data, err := patch.Data(obj); err == nil {
+			event = event.Str("patch_type", string(patch.Type())).RawJSON("patch", data)
+		}
+	} else if verb == "create" || verb == "update" {
+		event = event.Interface("object", obj)
+	}
+	event.Msg("Dry run: skipping write")
+}
+
+// dryRunSubResourceClient applies the dry-run behaviour to subresource writes such as status updates.
+type dryRunSubResourceClient struct {
+	client.SubResourceClient
+	parent      dryRunClient
+	subResource string
+}
+
+func (c dryRunSubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	c.parent.logWrite("create", c.subResource, obj, nil)
+	return nil
+}
+
+func (c dryRunSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	c.parent.logWrite("update", c.subResource, obj, nil)
+	return nil
+}
+
+func (c dryRunSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	c.parent.logWrite("patch", c.subResource, obj, patch)
+	return nil
+}
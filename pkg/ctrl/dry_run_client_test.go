@@ -0,0 +1,48 @@
+package ctrl
+
+import (
+	context "context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestDryRunClient_SkipsWrites(t *testing.T) {
+	ctx := context.Background()
+	existing := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Labels: map[string]string{"app": "web"}},
+	}
+	base := fake.NewClientBuilder().WithObjects(existing).Build()
+	c := newDryRunClient(base)
+
+	// Reads pass through.
+	var dep appsv1.Deployment
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(existing), &dep))
+
+	// Create is not sent.
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "companion", Namespace: "default"}}
+	require.NoError(t, c.Create(ctx, cm))
+	err := base.Get(ctx, client.ObjectKeyFromObject(cm), &corev1.ConfigMap{})
+	require.True(t, apierrors.IsNotFound(err), "expected ConfigMap not to be created, got %v", err)
+
+	// Patch and Update leave the stored object unchanged.
+	patch := client.MergeFrom(dep.DeepCopy())
+	dep.Labels["tier"] = "frontend"
+	require.NoError(t, c.Patch(ctx, &dep, patch))
+	require.NoError(t, c.Update(ctx, &dep))
+	require.NoError(t, c.Status().Update(ctx, &dep))
+
+	var stored appsv1.Deployment
+	require.NoError(t, base.Get(ctx, client.ObjectKeyFromObject(existing), &stored))
+	require.NotContains(t, stored.Labels, "tier")
+
+	// Delete is not sent.
+	require.NoError(t, c.Delete(ctx, &stored))
+	require.NoError(t, base.Get(ctx, client.ObjectKeyFromObject(existing), &appsv1.Deployment{}))
+}
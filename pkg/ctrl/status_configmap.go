@@ -0,0 +1,90 @@
+package ctrl
+
+import (
+	context "context"
+	"encoding/json"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// deploymentHealth is the JSON value stored under a deployment's key in the status ConfigMap.
+type deploymentHealth struct {
+	Ready       int32     `json:"ready"`
+	Desired     int32     `json:"desired"`
+	LastUpdated time.Time `json:"lastUpdated"`
+}
+
+// statusKey is the ConfigMap key of a deployment, "<namespace>.<name>". Both parts are
+// DNS names, so the key is always a valid ConfigMap key and cannot be ambiguous.
+func statusKey(key types.NamespacedName) string {
+	return key.Namespace + "." + key.Name
+}
+
+// statusClient reads and writes the status ConfigMap. It is uncached, so the controller
+// does not start a ConfigMap informer and needs no cluster-wide ConfigMap permissions,
+// and records FieldManager as the owner of what it writes.
+func (r *DeploymentReconciler) statusClient() client.Client {
+	c := r.StatusClient
+	if c == nil {
+		c = r.Client
+	}
+	if r.FieldManager != "" {
+		c = client.WithFieldOwner(c, r.FieldManager)
+	}
+	return c
+}
+
+// recordStatus upserts the health entry of deployment in the status ConfigMap. The
+// timestamp only moves when the counts change, so reconciles of an unchanged deployment
+// do not rewrite the ConfigMap.
+func (r *DeploymentReconciler) recordStatus(ctx context.Context, deployment *appsv1.Deployment, desired int32) error {
+	key := statusKey(client.ObjectKeyFromObject(deployment))
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: r.StatusConfigMap.Name, Namespace: r.StatusConfigMap.Namespace}}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.statusClient(), cm, func() error {
+		var previous deploymentHealth
+		if raw, ok := cm.Data[key]; ok {
+			_ = json.Unmarshal([]byte(raw), &previous)
+		}
+		if !previous.LastUpdated.IsZero() && previous.Ready == deployment.Status.ReadyReplicas && previous.Desired == desired {
+			return nil
+		}
+		value, err := json.Marshal(deploymentHealth{
+			Ready:       deployment.Status.ReadyReplicas,
+			Desired:     desired,
+			LastUpdated: time.Now().UTC().Truncate(time.Second),
+		})
+		if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[key] = string(value)
+		return nil
+	})
+	return err
+}
+
+// forgetStatus removes the entry of a deleted deployment from the status ConfigMap.
+func (r *DeploymentReconciler) forgetStatus(ctx context.Context, key types.NamespacedName) error {
+	c := r.statusClient()
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, r.StatusConfigMap, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if _, ok := cm.Data[statusKey(key)]; !ok {
+		return nil
+	}
+	delete(cm.Data, statusKey(key))
+	return c.Update(ctx, cm)
+}
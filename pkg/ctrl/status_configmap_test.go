@@ -0,0 +1,136 @@
+package ctrl
+
+import (
+	context "context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestDeploymentReconciler_StatusConfigMap(t *testing.T) {
+	ctx := context.Background()
+	web := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(3)},
+		Status:     appsv1.DeploymentStatus{ReadyReplicas: 1},
+	}
+	api := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "shop"},
+		Status:     appsv1.DeploymentStatus{ReadyReplicas: 1},
+	}
+	c := fake.NewClientBuilder().WithObjects(web, api).WithStatusSubresource(web).Build()
+	statusKey := types.NamespacedName{Namespace: "ops", Name: "k8s-controller-status"}
+	r := &DeploymentReconciler{Client: c, StatusConfigMap: statusKey}
+
+	reconcile := func(key client.ObjectKey) {
+		t.Helper()
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+	}
+	status := func() *corev1.ConfigMap {
+		t.Helper()
+		cm := &corev1.ConfigMap{}
+		require.NoError(t, c.Get(ctx, statusKey, cm))
+		return cm
+	}
+	entry := func(cm *corev1.ConfigMap, name string) deploymentHealth {
+		t.Helper()
+		var h deploymentHealth
+		require.NoError(t, json.Unmarshal([]byte(cm.Data[name]), &h))
+		return h
+	}
+
+	reconcile(client.ObjectKeyFromObject(web))
+	reconcile(client.ObjectKeyFromObject(api))
+	cm := status()
+	require.Len(t, cm.Data, 2)
+	got := entry(cm, "default.web")
+	require.Equal(t, int32(1), got.Ready)
+	require.Equal(t, int32(3), got.Desired)
+	require.False(t, got.LastUpdated.IsZero())
+	require.Equal(t, int32(1), entry(cm, "shop.api").Desired, "replicas default to 1")
+
+	// Deployments in every namespace share the one ConfigMap.
+	var cms corev1.ConfigMapList
+	require.NoError(t, c.List(ctx, &cms))
+	require.Len(t, cms.Items, 1)
+
+	// Reconciling an unchanged deployment leaves the ConfigMap alone.
+	reconcile(client.ObjectKeyFromObject(web))
+	require.Equal(t, cm.ResourceVersion, status().ResourceVersion)
+
+	// The entry follows the deployment as its replicas become ready.
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(web), web))
+	web.Status.ReadyReplicas = 3
+	require.NoError(t, c.Status().Update(ctx, web))
+	reconcile(client.ObjectKeyFromObject(web))
+	got = entry(status(), "default.web")
+	require.Equal(t, int32(3), got.Ready)
+	require.Equal(t, int32(3), got.Desired)
+
+	// Deleted deployments are dropped from the ConfigMap.
+	require.NoError(t, c.Delete(ctx, api))
+	reconcile(client.ObjectKeyFromObject(api))
+	cm = status()
+	require.NotContains(t, cm.Data, "shop.api")
+	require.Contains(t, cm.Data, "default.web")
+}
+
+func TestDeploymentReconciler_StatusClient(t *testing.T) {
+	ctx := context.Background()
+	dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+	c := fake.NewClientBuilder().WithObjects(dep).Build()
+	var fieldManagers []string
+	statusClient := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			fieldManagers = append(fieldManagers, (&client.CreateOptions{}).ApplyOptions(opts).FieldManager)
+			return c.Create(ctx, obj, opts...)
+		},
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			fieldManagers = append(fieldManagers, (&client.UpdateOptions{}).ApplyOptions(opts).FieldManager)
+			return c.Update(ctx, obj, opts...)
+		},
+	}).Build()
+	statusKey := types.NamespacedName{Namespace: "ops", Name: "k8s-controller-status"}
+	r := &DeploymentReconciler{Client: c, StatusClient: statusClient, StatusConfigMap: statusKey, FieldManager: "k8s-controller"}
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(dep)})
+	require.NoError(t, err)
+	cm := &corev1.ConfigMap{}
+	require.NoError(t, statusClient.Get(ctx, statusKey, cm))
+	require.Contains(t, cm.Data, "default.web")
+
+	require.NoError(t, c.Delete(ctx, dep))
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(dep)})
+	require.NoError(t, err)
+	require.Equal(t, []string{"k8s-controller", "k8s-controller"}, fieldManagers, "creating and pruning the entry must both set the field manager")
+
+	require.NoError(t, statusClient.Get(ctx, statusKey, cm))
+	require.NotContains(t, cm.Data, "default.web")
+	var cms corev1.ConfigMapList
+	require.NoError(t, c.List(ctx, &cms))
+	require.Empty(t, cms.Items, "the status ConfigMap must only go through StatusClient")
+}
+
+func TestDeploymentReconciler_StatusConfigMapDisabled(t *testing.T) {
+	ctx := context.Background()
+	dep := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}}
+	c := fake.NewClientBuilder().WithObjects(dep).Build()
+	r := &DeploymentReconciler{Client: c}
+
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "web"}})
+	require.NoError(t, err)
+
+	var cms corev1.ConfigMapList
+	require.NoError(t, c.List(ctx, &cms))
+	require.Empty(t, cms.Items)
+}
@@ -0,0 +1,97 @@
+// Package deploystatus decides when a Deployment has reached a requested state, so the
+// CLI wait commands and test helpers agree on what "ready" means.
+package deploystatus
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Condition is a state a deployment can be waited for. The zero value is Available.
+type Condition struct {
+	kind          string
+	conditionType appsv1.DeploymentConditionType
+}
+
+const (
+	kindAvailable = "available"
+	kindUpdated   = "updated"
+	kindComplete  = "complete"
+	kindCondition = "condition"
+)
+
+var (
+	// Available is met once the available replicas reach the desired count.
+	Available = Condition{kind: kindAvailable}
+	// Updated is met once the updated replicas reach the desired count.
+	Updated = Condition{kind: kindUpdated}
+	// Complete is met once the rolling update is done: the controller has observed the
+	// latest generation and every replica is updated and available, with no old ones left.
+	Complete = Condition{kind: kindComplete}
+)
+
+// HasCondition is met while the deployment reports the status condition t as True,
+// e.g. HasCondition(appsv1.DeploymentAvailable).
+func HasCondition(t appsv1.DeploymentConditionType) Condition {
+	return Condition{kind: kindCondition, conditionType: t}
+}
+
+// ParseCondition parses a --for value: available, updated, complete or condition=TYPE.
+// An empty value selects Available.
+func ParseCondition(s string) (Condition, error) {
+	switch strings.ToLower(s) {
+	case "", kindAvailable:
+		return Available, nil
+	case kindUpdated:
+		return Updated, nil
+	case kindComplete:
+		return Complete, nil
+	}
+	if key, value, ok := strings.Cut(s, "="); ok && strings.EqualFold(key, kindCondition) && value != "" {
+		return HasCondition(appsv1.DeploymentConditionType(value)), nil
+	}
+	return Condition{}, fmt.Errorf("unknown condition %q, must be available, updated, complete or condition=TYPE", s)
+}
+
+// String returns the condition in the form accepted by ParseCondition.
+func (c Condition) String() string {
+	switch c.kind {
+	case "":
+		return kindAvailable
+	case kindCondition:
+		return kindCondition + "=" + string(c.conditionType)
+	default:
+		return c.kind
+	}
+}
+
+// Met reports whether d is in the state described by c.
+func (c Condition) Met(d *appsv1.Deployment) bool {
+	desired := int32(1)
+	if d.Spec.Replicas != nil {
+		desired = *d.Spec.Replicas
+	}
+	status := d.Status
+
+	switch c.kind {
+	case kindUpdated:
+		return status.UpdatedReplicas >= desired
+	case kindComplete:
+		return status.ObservedGeneration >= d.Generation &&
+			status.UpdatedReplicas == desired &&
+			status.Replicas == status.UpdatedReplicas &&
+			status.AvailableReplicas == status.UpdatedReplicas
+	case kindCondition:
+		for _, cond := range status.Conditions {
+			if cond.Type == c.conditionType {
+				return cond.Status == corev1.ConditionTrue
+			}
+		}
+		return false
+	default:
+		return status.AvailableReplicas >= desired
+	}
+}
@@ -0,0 +1,83 @@
+package deploystatus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func deployment(generation, observed int64, replicas, updated, available int32) *appsv1.Deployment {
+	desired := int32(3)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Generation: generation},
+		Spec:       appsv1.DeploymentSpec{Replicas: &desired},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: observed,
+			Replicas:           replicas,
+			UpdatedReplicas:    updated,
+			AvailableReplicas:  available,
+		},
+	}
+}
+
+func TestParseCondition(t *testing.T) {
+	tests := map[string]Condition{
+		"":                    Available,
+		"available":           Available,
+		"Updated":             Updated,
+		"complete":            Complete,
+		"condition=Available": HasCondition(appsv1.DeploymentAvailable),
+	}
+	for in, want := range tests {
+		got, err := ParseCondition(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, got, in)
+	}
+
+	for _, in := range []string{"ready", "condition=", "status=Available"} {
+		_, err := ParseCondition(in)
+		require.Error(t, err, in)
+	}
+}
+
+func TestConditionString(t *testing.T) {
+	require.Equal(t, "available", Condition{}.String())
+	require.Equal(t, "complete", Complete.String())
+	require.Equal(t, "condition=Progressing", HasCondition(appsv1.DeploymentProgressing).String())
+}
+
+func TestConditionMet(t *testing.T) {
+	tests := []struct {
+		name       string
+		deployment *appsv1.Deployment
+		available  bool
+		updated    bool
+		complete   bool
+	}{
+		{"rolled out", deployment(2, 2, 3, 3, 3), true, true, true},
+		{"old replicas still running", deployment(2, 2, 4, 3, 3), true, true, false},
+		{"spec change not observed", deployment(3, 2, 3, 3, 3), true, true, false},
+		{"updated but not available", deployment(2, 2, 3, 3, 1), false, true, false},
+		{"available on the old version", deployment(2, 2, 3, 1, 3), true, false, false},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.available, Available.Met(tt.deployment), "%s: available", tt.name)
+		require.Equal(t, tt.updated, Updated.Met(tt.deployment), "%s: updated", tt.name)
+		require.Equal(t, tt.complete, Complete.Met(tt.deployment), "%s: complete", tt.name)
+	}
+}
+
+func TestHasConditionMet(t *testing.T) {
+	d := deployment(1, 1, 3, 3, 3)
+	cond := HasCondition(appsv1.DeploymentAvailable)
+	require.False(t, cond.Met(d))
+
+	d.Status.Conditions = []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionFalse}}
+	require.False(t, cond.Met(d))
+
+	d.Status.Conditions[0].Status = corev1.ConditionTrue
+	require.True(t, cond.Met(d))
+}
@@ -0,0 +1,75 @@
+package informer
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ConfigError reports an InformerConfig field that breaks one of the rules of Validate.
+type ConfigError struct {
+	// Field is the name of the offending InformerConfig field, e.g. "ResyncPeriod".
+	Field  string
+	Reason string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("invalid informer config: %s %s", e.Field, e.Reason)
+}
+
+// WithDefaults returns a copy of c with unset fields filled in: a 30s ResyncPeriod, and
+// DefaultExcludeNamespaces when watching all namespaces without ExcludeNamespaces.
+func (c InformerConfig) WithDefaults() InformerConfig {
+	if c.ResyncPeriod == 0 {
+		c.ResyncPeriod = defaultResyncPeriod
+	}
+	if c.ExcludeNamespaces == nil && c.Namespace == metav1.NamespaceAll {
+		c.ExcludeNamespaces = DefaultExcludeNamespaces
+	}
+	return c
+}
+
+// Validate checks c and returns a *ConfigError for the first rule it breaks. Without a
+// Clientset exactly one of Kubeconfig and InCluster must be set; with one both are
+// ignored. Durations may not be negative, namespaces must be valid names, LabelSelector
+// must parse and WebhookURL must be an absolute http or https URL.
+func (c InformerConfig) Validate() error {
+	if c.Clientset == nil {
+		switch {
+		case c.Kubeconfig != "" && c.InCluster:
+			return &ConfigError{Field: "Kubeconfig", Reason: "cannot be combined with InCluster"}
+		case c.Kubeconfig == "" && !c.InCluster:
+			return &ConfigError{Field: "Kubeconfig", Reason: "or InCluster is required when no Clientset is given"}
+		}
+	}
+	if c.ResyncPeriod < 0 {
+		return &ConfigError{Field: "ResyncPeriod", Reason: fmt.Sprintf("must not be negative, got %s", c.ResyncPeriod)}
+	}
+	if c.StaleThreshold < 0 {
+		return &ConfigError{Field: "StaleThreshold", Reason: fmt.Sprintf("must not be negative, got %s", c.StaleThreshold)}
+	}
+	if c.Namespace != metav1.NamespaceAll {
+		if errs := validation.IsDNS1123Label(c.Namespace); len(errs) > 0 {
+			return &ConfigError{Field: "Namespace", Reason: fmt.Sprintf("%q is not a valid namespace: %s", c.Namespace, strings.Join(errs, "; "))}
+		}
+	}
+	for _, ns := range c.ExcludeNamespaces {
+		if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+			return &ConfigError{Field: "ExcludeNamespaces", Reason: fmt.Sprintf("%q is not a valid namespace: %s", ns, strings.Join(errs, "; "))}
+		}
+	}
+	if _, err := labels.Parse(c.LabelSelector); err != nil {
+		return &ConfigError{Field: "LabelSelector", Reason: err.Error()}
+	}
+	if c.WebhookURL != "" {
+		u, err := url.Parse(c.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return &ConfigError{Field: "WebhookURL", Reason: fmt.Sprintf("%q is not an absolute http or https URL", c.WebhookURL)}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package informer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestInformerConfig_WithDefaults(t *testing.T) {
+	cfg := InformerConfig{}.WithDefaults()
+	require.Equal(t, defaultResyncPeriod, cfg.ResyncPeriod)
+	require.Equal(t, DefaultExcludeNamespaces, cfg.ExcludeNamespaces)
+
+	cfg = InformerConfig{Namespace: "default", ResyncPeriod: time.Minute}.WithDefaults()
+	require.Equal(t, time.Minute, cfg.ResyncPeriod)
+	require.Nil(t, cfg.ExcludeNamespaces, "a single namespace gets no exclusions")
+
+	cfg = InformerConfig{ExcludeNamespaces: []string{}}.WithDefaults()
+	require.Empty(t, cfg.ExcludeNamespaces)
+	require.NotNil(t, cfg.ExcludeNamespaces, "an empty slice is kept to exclude nothing")
+}
+
+func TestInformerConfig_Validate(t *testing.T) {
+	clientset := fake.NewClientset()
+	tests := []struct {
+		name      string
+		cfg       InformerConfig
+		wantField string
+	}{
+		{name: "kubeconfig", cfg: InformerConfig{Kubeconfig: "/home/user/.kube/config"}},
+		{name: "in cluster", cfg: InformerConfig{InCluster: true}},
+		{name: "clientset ignores auth fields", cfg: InformerConfig{Clientset: clientset, Kubeconfig: "/x", InCluster: true}},
+		{name: "everything set", cfg: InformerConfig{
+			Clientset:         clientset,
+			Namespace:         "default",
+			ResyncPeriod:      time.Minute,
+			StaleThreshold:    5 * time.Minute,
+			ExcludeNamespaces: []string{"kube-system"},
+			LabelSelector:     "tier in (web,api),!canary",
+			WebhookURL:        "https://hooks.example.com/deployments",
+		}},
+		{name: "no auth mode", cfg: InformerConfig{}, wantField: "Kubeconfig"},
+		{name: "both auth modes", cfg: InformerConfig{Kubeconfig: "/x", InCluster: true}, wantField: "Kubeconfig"},
+		{name: "negative resync", cfg: InformerConfig{Clientset: clientset, ResyncPeriod: -time.Second}, wantField: "ResyncPeriod"},
+		{name: "negative stale threshold", cfg: InformerConfig{Clientset: clientset, StaleThreshold: -time.Second}, wantField: "StaleThreshold"},
+		{name: "invalid namespace", cfg: InformerConfig{Clientset: clientset, Namespace: "Prod_1"}, wantField: "Namespace"},
+		{name: "invalid excluded namespace", cfg: InformerConfig{Clientset: clientset, ExcludeNamespaces: []string{"kube system"}}, wantField: "ExcludeNamespaces"},
+		{name: "invalid selector", cfg: InformerConfig{Clientset: clientset, LabelSelector: "tier in web"}, wantField: "LabelSelector"},
+		{name: "relative webhook URL", cfg: InformerConfig{Clientset: clientset, WebhookURL: "/hooks"}, wantField: "WebhookURL"},
+		{name: "non-http webhook URL", cfg: InformerConfig{Clientset: clientset, WebhookURL: "ftp://hooks.example.com"}, wantField: "WebhookURL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantField == "" {
+				require.NoError(t, err)
+				return
+			}
+			var cfgErr *ConfigError
+			require.True(t, errors.As(err, &cfgErr), "expected a *ConfigError, got %v", err)
+			require.Equal(t, tt.wantField, cfgErr.Field)
+		})
+	}
+}
+
+func TestNewDeploymentInformer_ValidatesConfig(t *testing.T) {
+	_, err := NewDeploymentInformer(InformerConfig{Clientset: fake.NewClientset(), ResyncPeriod: -time.Second})
+	var cfgErr *ConfigError
+	require.ErrorAs(t, err, &cfgErr)
+	require.EqualError(t, err, "invalid informer config: ResyncPeriod must not be negative, got -1s")
+}
+
+func TestDeploymentInformer_LabelSelector(t *testing.T) {
+	di := startTestInformer(t, InformerConfig{Namespace: "default", LabelSelector: "tier=web"},
+		testDeployment("default", "web", map[string]string{"tier": "web"}),
+		testDeployment("default", "db", map[string]string{"tier": "db"}),
+	)
+
+	deployments, err := di.List("", nil)
+	require.NoError(t, err)
+	require.Len(t, deployments, 1)
+	require.Equal(t, "web", deployments[0].Name)
+}
@@ -0,0 +1,450 @@
+package informer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/clock"
+)
+
+// labelIndex indexes deployments by each of their "key=value" label pairs.
+const labelIndex = "labels"
+
+const defaultResyncPeriod = 30 * time.Second
+
+// DefaultExcludeNamespaces are the system namespaces whose events are ignored when
+// watching all namespaces and InformerConfig.ExcludeNamespaces is nil.
+var DefaultExcludeNamespaces = []string{"kube-system", "kube-public", "kube-node-lease"}
+
+// InformerConfig holds the settings used to build a DeploymentInformer.
+type InformerConfig struct {
+	// Kubeconfig is the path to the kubeconfig file, or several paths separated like
+	// KUBECONFIG that are merged. Without a Clientset exactly one of
+	// Kubeconfig and InCluster must be set, see Validate.
+	Kubeconfig string
+	// InCluster uses the service account of the pod the informer runs in. It cannot be
+	// combined with Kubeconfig.
+	InCluster bool
+	// Namespace limits the informer to a single namespace. Empty watches all namespaces.
+	Namespace string
+	// ResyncPeriod controls how often cached objects are re-delivered to handlers (default 30s).
+	ResyncPeriod time.Duration
+	// StaleThreshold marks the cache as degraded when no event or resync has been seen for
+	// this long after the initial sync. Zero disables the check.
+	StaleThreshold time.Duration
+	// Clientset, when set, is used as-is instead of building a client from Kubeconfig or
+	// InCluster. Tests can pass a fake clientset pre-loaded with objects.
+	Clientset kubernetes.Interface
+	// WebhookURL, when set, receives a JSON POST for every deployment add, update and
+	// delete. Delivery is asynchronous and best-effort; failed events are logged and dropped.
+	WebhookURL string
+	// ExcludeNamespaces lists namespaces whose deployment events are not passed to event
+	// handlers. Nil means DefaultExcludeNamespaces when watching all namespaces and nothing
+	// otherwise; an empty slice excludes nothing. Excluded deployments are still cached.
+	ExcludeNamespaces []string
+	// LabelSelector, when set, limits the informer to deployments matching it, e.g. "tier=web".
+	LabelSelector string
+}
+
+// Cache is a read-only view of the deployments held in an informer's store.
+type Cache interface {
+	// Get returns the deployment with the given namespace and name, or a NotFound API error.
+	Get(namespace, name string) (*appsv1.Deployment, error)
+	// List returns the deployments in namespace (all namespaces if empty) matching selector.
+	List(namespace string, selector labels.Selector) ([]*appsv1.Deployment, error)
+}
+
+// DeploymentInformer watches Deployments and serves them from its local cache.
+type DeploymentInformer struct {
+	config   InformerConfig
+	factory  informers.SharedInformerFactory
+	informer cache.SharedIndexInformer
+	cancel   context.CancelFunc
+	webhook  *webhookNotifier
+	excluded map[string]bool
+
+	clock clock.PassiveClock
+	// lastSync is the UnixNano time of the last event, resync or initial sync.
+	lastSync atomic.Int64
+
+	startedAt   time.Time
+	adds        atomic.Int64
+	updates     atomic.Int64
+	resyncs     atomic.Int64
+	deletes     atomic.Int64
+	summaryOnce sync.Once
+}
+
+// Stats counts the events a DeploymentInformer has handled since it was started.
+type Stats struct {
+	Adds      int64
+	Updates   int64
+	Resyncs   int64
+	Deletes   int64
+	CacheSize int
+	Uptime    time.Duration
+}
+
+var _ Cache = (*DeploymentInformer)(nil)
+
+// NewDeploymentInformer returns an informer that is not yet started. cfg is defaulted and
+// validated first, see InformerConfig.Validate. The Kubernetes client is cfg.Clientset
+// when set, otherwise it is built from cfg.
+func NewDeploymentInformer(cfg InformerConfig) (*DeploymentInformer, error) {
+	cfg = cfg.WithDefaults()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	clientset := cfg.Clientset
+	if clientset == nil {
+		var err error
+		if clientset, err = newClientset(cfg); err != nil {
+			return nil, err
+		}
+	}
+	return newDeploymentInformer(clientset, cfg)
+}
+
+func newClientset(cfg InformerConfig) (kubernetes.Interface, error) {
+	config, err := restConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build config: %w", err)
+	}
+	return kubernetes.NewForConfig(config)
+}
+
+// restConfig loads cfg.Kubeconfig with the client-go loading rules, so a list separated
+// like KUBECONFIG is merged the way kubectl merges it. A single path must exist.
+func restConfig(cfg InformerConfig) (*rest.Config, error) {
+	if cfg.InCluster {
+		return rest.InClusterConfig()
+	}
+	rules := &clientcmd.ClientConfigLoadingRules{}
+	if paths := filepath.SplitList(cfg.Kubeconfig); len(paths) > 1 {
+		rules.Precedence = paths
+	} else {
+		rules.ExplicitPath = cfg.Kubeconfig
+	}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).ClientConfig()
+}
+
+func newDeploymentInformer(clientset kubernetes.Interface, cfg InformerConfig) (*DeploymentInformer, error) {
+	factory := informers.NewSharedInformerFactoryWithOptions(
+		clientset,
+		cfg.ResyncPeriod,
+		informers.WithNamespace(cfg.Namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.Everything().String()
+			options.LabelSelector = cfg.LabelSelector
+		}),
+	)
+	informer := factory.Apps().V1().Deployments().Informer()
+	if err := informer.AddIndexers(cache.Indexers{labelIndex: indexByLabels}); err != nil {
+		return nil, fmt.Errorf("failed to add label index: %w", err)
+	}
+
+	d := &DeploymentInformer{
+		config:   cfg,
+		factory:  factory,
+		informer: informer,
+		excluded: make(map[string]bool, len(cfg.ExcludeNamespaces)),
+		clock:    clock.RealClock{},
+	}
+	for _, ns := range cfg.ExcludeNamespaces {
+		d.excluded[ns] = true
+	}
+	// Resyncs are delivered as updates, so every handler call proves the informer is alive.
+	// This handler bypasses the namespace filter: events from excluded namespaces still
+	// keep the informer from being reported as degraded and count towards Stats, they are
+	// only kept out of the logs and user handlers.
+	_, err := d.informer.AddEventHandler(recoveringHandler{resource: "deployment", handler: cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			d.markSynced()
+			d.adds.Add(1)
+			if d.included(obj) {
+				log.Info().Msgf("Deployment added: %s", getDeploymentName(obj))
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			d.markSynced()
+			if isResync(oldObj, newObj) {
+				d.resyncs.Add(1)
+			} else {
+				d.updates.Add(1)
+			}
+			if d.included(newObj) {
+				log.Info().Msgf("Deployment updated: %s", getDeploymentName(newObj))
+				logImageChanges(oldObj, newObj)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			d.markSynced()
+			d.deletes.Add(1)
+			if d.included(obj) {
+				log.Info().Msgf("Deployment deleted: %s", getDeploymentName(obj))
+			}
+		},
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add event handler: %w", err)
+	}
+	if cfg.WebhookURL != "" {
+		d.webhook = newWebhookNotifier(cfg.WebhookURL)
+		if _, err := d.AddEventHandler(d.webhook.handler()); err != nil {
+			return nil, fmt.Errorf("failed to add webhook handler: %w", err)
+		}
+	}
+	return d, nil
+}
+
+// AddEventHandler registers a handler for deployment events. Events from excluded
+// namespaces are dropped before they reach the handler. A panic inside the handler is
+// logged with its stack trace and the informer moves on to the next event.
+func (d *DeploymentInformer) AddEventHandler(handler cache.ResourceEventHandler) (cache.ResourceEventHandlerRegistration, error) {
+	var h cache.ResourceEventHandler = recoveringHandler{resource: "deployment", handler: handler}
+	if len(d.excluded) > 0 {
+		h = cache.FilteringResourceEventHandler{FilterFunc: d.included, Handler: h}
+	}
+	return d.informer.AddEventHandler(h)
+}
+
+// included reports whether obj is outside the excluded namespaces.
+func (d *DeploymentInformer) included(obj interface{}) bool {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	meta, ok := obj.(metav1.Object)
+	return !ok || !d.excluded[meta.GetNamespace()]
+}
+
+// Start runs the informer in the background until ctx is cancelled or Stop is called.
+// A summary of the handled events is logged when it stops.
+func (d *DeploymentInformer) Start(ctx context.Context) {
+	ctx, d.cancel = context.WithCancel(ctx)
+	d.startedAt = d.clock.Now()
+	if len(d.config.ExcludeNamespaces) > 0 {
+		log.Info().Strs("namespaces", d.config.ExcludeNamespaces).Msg("Ignoring deployment events from excluded namespaces")
+	}
+	if d.webhook != nil {
+		go d.webhook.run(ctx)
+	}
+	d.factory.Start(ctx.Done())
+	go func() {
+		<-ctx.Done()
+		d.logSummary()
+	}()
+}
+
+// WaitForCacheSync blocks until the initial list has been cached or ctx is done.
+func (d *DeploymentInformer) WaitForCacheSync(ctx context.Context) bool {
+	if !cache.WaitForCacheSync(ctx.Done(), d.informer.HasSynced) {
+		return false
+	}
+	d.markSynced()
+	return true
+}
+
+// HasSynced reports whether the initial list has been cached.
+func (d *DeploymentInformer) HasSynced() bool {
+	return d.informer.HasSynced()
+}
+
+// Degraded reports whether the synced cache has seen no event or resync within
+// StaleThreshold, which points at a wedged watch. An empty cache is never degraded
+// because resyncs have nothing to deliver.
+func (d *DeploymentInformer) Degraded() bool {
+	if d.config.StaleThreshold <= 0 || !d.HasSynced() || len(d.informer.GetStore().ListKeys()) == 0 {
+		return false
+	}
+	last := d.lastSync.Load()
+	if last == 0 {
+		return false
+	}
+	return d.clock.Since(time.Unix(0, last)) > d.config.StaleThreshold
+}
+
+func (d *DeploymentInformer) markSynced() {
+	d.lastSync.Store(d.clock.Now().UnixNano())
+}
+
+// Stop stops the informer and waits for its goroutines to exit.
+func (d *DeploymentInformer) Stop() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	d.factory.Shutdown()
+	d.logSummary()
+}
+
+// Stats returns the event counts and cache size so far.
+func (d *DeploymentInformer) Stats() Stats {
+	stats := Stats{
+		Adds:      d.adds.Load(),
+		Updates:   d.updates.Load(),
+		Resyncs:   d.resyncs.Load(),
+		Deletes:   d.deletes.Load(),
+		CacheSize: len(d.informer.GetStore().ListKeys()),
+	}
+	if !d.startedAt.IsZero() {
+		stats.Uptime = d.clock.Since(d.startedAt)
+	}
+	return stats
+}
+
+// logSummary logs the final Stats once, however the informer was stopped.
+func (d *DeploymentInformer) logSummary() {
+	d.summaryOnce.Do(func() {
+		stats := d.Stats()
+		log.Info().
+			Int64("adds", stats.Adds).
+			Int64("updates", stats.Updates).
+			Int64("resyncs", stats.Resyncs).
+			Int64("deletes", stats.Deletes).
+			Int("cache_size", stats.CacheSize).
+			Dur("uptime", stats.Uptime).
+			Msg("Deployment informer stopped")
+	})
+}
+
+// Get returns the cached deployment. Misses return apierrors.NewNotFound, so
+// callers can check them with apierrors.IsNotFound, which also matches wrapped errors.
+func (d *DeploymentInformer) Get(namespace, name string) (*appsv1.Deployment, error) {
+	key := name
+	if namespace != "" {
+		key = namespace + "/" + name
+	}
+	obj, exists, err := d.informer.GetIndexer().GetByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	dep, ok := obj.(*appsv1.Deployment)
+	if !exists || !ok {
+		return nil, apierrors.NewNotFound(appsv1.Resource("deployments"), name)
+	}
+	return dep, nil
+}
+
+// List returns cached deployments sorted by namespace and name. A nil selector matches everything.
+func (d *DeploymentInformer) List(namespace string, selector labels.Selector) ([]*appsv1.Deployment, error) {
+	if selector == nil {
+		selector = labels.Everything()
+	}
+	objs, err := d.candidates(namespace, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var deployments []*appsv1.Deployment
+	for _, obj := range objs {
+		dep, ok := obj.(*appsv1.Deployment)
+		if !ok {
+			continue
+		}
+		if namespace != "" && dep.Namespace != namespace {
+			continue
+		}
+		if !selector.Matches(labels.Set(dep.Labels)) {
+			continue
+		}
+		deployments = append(deployments, dep)
+	}
+	sort.Slice(deployments, func(i, j int) bool {
+		if deployments[i].Namespace != deployments[j].Namespace {
+			return deployments[i].Namespace < deployments[j].Namespace
+		}
+		return deployments[i].Name < deployments[j].Name
+	})
+	return deployments, nil
+}
+
+// candidates narrows the objects to scan using the label index when the selector
+// has an equality requirement, and the namespace index otherwise.
+func (d *DeploymentInformer) candidates(namespace string, selector labels.Selector) ([]interface{}, error) {
+	indexer := d.informer.GetIndexer()
+	if requirements, selectable := selector.Requirements(); selectable {
+		for _, r := range requirements {
+			values := r.Values()
+			switch r.Operator() {
+			case selection.Equals, selection.DoubleEquals, selection.In:
+				if values.Len() == 1 {
+					return indexer.ByIndex(labelIndex, r.Key()+"="+values.List()[0])
+				}
+			}
+		}
+	}
+	if namespace != "" {
+		return indexer.ByIndex(cache.NamespaceIndex, namespace)
+	}
+	return indexer.List(), nil
+}
+
+// recoveringHandler isolates panics in a wrapped handler to the event that caused them.
+// resource names the kind of object in the log, e.g. "deployment".
+type recoveringHandler struct {
+	resource string
+	handler  cache.ResourceEventHandler
+}
+
+func (h recoveringHandler) OnAdd(obj interface{}, isInInitialList bool) {
+	defer h.recoverPanic("add", obj)
+	h.handler.OnAdd(obj, isInInitialList)
+}
+
+func (h recoveringHandler) OnUpdate(oldObj, newObj interface{}) {
+	defer h.recoverPanic("update", newObj)
+	h.handler.OnUpdate(oldObj, newObj)
+}
+
+func (h recoveringHandler) OnDelete(obj interface{}) {
+	defer h.recoverPanic("delete", obj)
+	h.handler.OnDelete(obj)
+}
+
+func (h recoveringHandler) recoverPanic(event string, obj interface{}) {
+	if r := recover(); r != nil {
+		log.Error().
+			Str("event", event).
+			Str(h.resource, getDeploymentName(obj)).
+			Interface("panic", r).
+			Str("stack", string(debug.Stack())).
+			Msgf("Recovered from panic in %s event handler", h.resource)
+	}
+}
+
+// isResync reports whether an update is a periodic resync, which re-delivers an
+// unchanged object.
+func isResync(oldObj, newObj interface{}) bool {
+	oldMeta, okOld := oldObj.(metav1.Object)
+	newMeta, okNew := newObj.(metav1.Object)
+	return okOld && okNew && oldMeta.GetResourceVersion() == newMeta.GetResourceVersion()
+}
+
+func indexByLabels(obj interface{}) ([]string, error) {
+	meta, ok := obj.(metav1.Object)
+	if !ok {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(meta.GetLabels()))
+	for k, v := range meta.GetLabels() {
+		keys = append(keys, k+"="+v)
+	}
+	return keys, nil
+}
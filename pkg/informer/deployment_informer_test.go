@@ -0,0 +1,338 @@
+package informer
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func testDeployment(namespace, name string, lbls map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: lbls},
+	}
+}
+
+func startTestInformer(t *testing.T, cfg InformerConfig, objects ...runtime.Object) *DeploymentInformer {
+	t.Helper()
+	cfg.Clientset = fake.NewClientset(objects...)
+	di, err := NewDeploymentInformer(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		di.Stop()
+	})
+	di.Start(ctx)
+	require.True(t, di.WaitForCacheSync(ctx))
+	return di
+}
+
+func TestDeploymentInformer_Get(t *testing.T) {
+	di := startTestInformer(t, InformerConfig{},
+		testDeployment("default", "web", nil),
+		testDeployment("staging", "web", nil),
+	)
+
+	dep, err := di.Get("staging", "web")
+	require.NoError(t, err)
+	require.Equal(t, "staging", dep.Namespace)
+
+	_, err = di.Get("default", "missing")
+	require.True(t, apierrors.IsNotFound(err), "expected NotFound, got %v", err)
+}
+
+func TestDeploymentInformer_List(t *testing.T) {
+	di := startTestInformer(t, InformerConfig{},
+		testDeployment("default", "web", map[string]string{"app": "web", "tier": "frontend"}),
+		testDeployment("default", "api", map[string]string{"app": "api", "tier": "backend"}),
+		testDeployment("staging", "web", map[string]string{"app": "web", "tier": "frontend"}),
+	)
+
+	tests := []struct {
+		name      string
+		namespace string
+		selector  string
+		want      []string
+	}{
+		{"all", "", "", []string{"default/api", "default/web", "staging/web"}},
+		{"namespace", "default", "", []string{"default/api", "default/web"}},
+		{"equality selector", "", "app=web", []string{"default/web", "staging/web"}},
+		{"equality selector in namespace", "staging", "tier=frontend", []string{"staging/web"}},
+		{"set selector", "", "tier in (frontend,backend),app!=web", []string{"default/api"}},
+		{"no match", "", "app=missing", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selector, err := labels.Parse(tt.selector)
+			require.NoError(t, err)
+			deployments, err := di.List(tt.namespace, selector)
+			require.NoError(t, err)
+
+			var got []string
+			for _, d := range deployments {
+				got = append(got, d.Namespace+"/"+d.Name)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDeploymentInformer_ListNilSelector(t *testing.T) {
+	di := startTestInformer(t, InformerConfig{Namespace: "default"},
+		testDeployment("default", "web", nil),
+	)
+	deployments, err := di.List("", nil)
+	require.NoError(t, err)
+	require.Len(t, deployments, 1)
+}
+
+func TestNewDeploymentInformer_InvalidKubeconfig(t *testing.T) {
+	_, err := NewDeploymentInformer(InformerConfig{Kubeconfig: "/invalid/path"})
+	require.Error(t, err)
+}
+
+func TestRestConfig_MergesKubeconfigList(t *testing.T) {
+	// a only selects the context; the context, cluster and user live in b.
+	a, b := testutil.WriteSplitKubeconfig(t)
+
+	config, err := restConfig(InformerConfig{Kubeconfig: a + string(os.PathListSeparator) + b})
+	require.NoError(t, err)
+	require.Equal(t, testutil.SplitKubeconfigServer, config.Host)
+	require.Equal(t, testutil.SplitKubeconfigToken, config.BearerToken)
+
+	_, err = restConfig(InformerConfig{Kubeconfig: a})
+	require.Error(t, err, "a itself defines no context")
+}
+
+func TestNewDeploymentInformer_ClientsetBypassesKubeconfig(t *testing.T) {
+	di := startTestInformer(t, InformerConfig{Kubeconfig: "/invalid/path"}, testDeployment("default", "web", nil))
+
+	dep, err := di.Get("default", "web")
+	require.NoError(t, err)
+	require.Equal(t, "web", dep.Name)
+}
+
+func TestDeploymentInformer_RecoversHandlerPanic(t *testing.T) {
+	clientset := fake.NewClientset()
+	di, err := NewDeploymentInformer(InformerConfig{Clientset: clientset})
+	require.NoError(t, err)
+
+	delivered := make(chan string, 2)
+	_, err = di.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			dep := obj.(*appsv1.Deployment)
+			if dep.Name == "bad" {
+				panic("bad deployment")
+			}
+			delivered <- dep.Name
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			_ = oldObj.(*corev1.Pod) // wrong type assertion panics
+		},
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	di.Start(ctx)
+	defer di.Stop()
+	require.True(t, di.WaitForCacheSync(ctx))
+
+	deployments := clientset.AppsV1().Deployments("default")
+	_, err = deployments.Create(ctx, testDeployment("default", "bad", nil), metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = deployments.Update(ctx, testDeployment("default", "bad", map[string]string{"app": "bad"}), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	_, err = deployments.Create(ctx, testDeployment("default", "good", nil), metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	select {
+	case name := <-delivered:
+		require.Equal(t, "good", name)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event after handler panic")
+	}
+}
+
+func TestDeploymentInformer_DegradedWhenStale(t *testing.T) {
+	clientset := fake.NewClientset(testDeployment("default", "web", nil))
+	di, err := NewDeploymentInformer(InformerConfig{Clientset: clientset, StaleThreshold: time.Minute})
+	require.NoError(t, err)
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	di.clock = fakeClock
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		di.Stop()
+	})
+	di.Start(ctx)
+	require.True(t, di.WaitForCacheSync(ctx))
+	require.False(t, di.Degraded())
+
+	fakeClock.Step(2 * time.Minute)
+	require.True(t, di.Degraded())
+
+	// Any event, including a resync, marks the cache fresh again.
+	dep := testDeployment("default", "web", map[string]string{"app": "web"})
+	_, err = clientset.AppsV1().Deployments("default").Update(ctx, dep, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return !di.Degraded() }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestDeploymentInformer_EmptyCacheNeverDegraded(t *testing.T) {
+	di, err := NewDeploymentInformer(InformerConfig{Clientset: fake.NewClientset(), StaleThreshold: time.Minute})
+	require.NoError(t, err)
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	di.clock = fakeClock
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		di.Stop()
+	})
+	di.Start(ctx)
+	require.True(t, di.WaitForCacheSync(ctx))
+
+	fakeClock.Step(time.Hour)
+	require.False(t, di.Degraded())
+}
+
+func TestDeploymentInformer_LogsSummaryOnStop(t *testing.T) {
+	var logs syncBuffer
+	originalLogger := log.Logger
+	log.Logger = zerolog.New(&logs)
+	t.Cleanup(func() { log.Logger = originalLogger })
+
+	clientset := fake.NewClientset(testDeployment("default", "web", nil))
+	di, err := NewDeploymentInformer(InformerConfig{Clientset: clientset})
+	require.NoError(t, err)
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	di.clock = fakeClock
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	di.Start(ctx)
+	require.True(t, di.WaitForCacheSync(ctx))
+
+	deployments := clientset.AppsV1().Deployments("default")
+	_, err = deployments.Create(ctx, testDeployment("default", "api", nil), metav1.CreateOptions{})
+	require.NoError(t, err)
+	updated := testDeployment("default", "web", map[string]string{"app": "web"})
+	updated.ResourceVersion = "2"
+	_, err = deployments.Update(ctx, updated, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, deployments.Delete(ctx, "api", metav1.DeleteOptions{}))
+
+	require.Eventually(t, func() bool {
+		stats := di.Stats()
+		return stats.Adds == 2 && stats.Updates == 1 && stats.Deletes == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	fakeClock.Step(90 * time.Second)
+	di.Stop()
+	di.Stop()
+
+	out := logs.String()
+	require.Equal(t, 1, strings.Count(out, "Deployment informer stopped"), "summary should be logged once:\n%s", out)
+	require.Contains(t, out, `"adds":2`)
+	require.Contains(t, out, `"updates":1`)
+	require.Contains(t, out, `"deletes":1`)
+	require.Contains(t, out, `"cache_size":1`)
+	require.Contains(t, out, `"uptime":90000`)
+}
+
+func TestDeploymentInformer_ExcludeNamespaces(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     InformerConfig
+		wantAll []string
+	}{
+		{name: "default excludes system namespaces", cfg: InformerConfig{}, wantAll: []string{"default/web"}},
+		{name: "empty slice excludes nothing", cfg: InformerConfig{ExcludeNamespaces: []string{}}, wantAll: []string{"default/web", "kube-system/coredns"}},
+		{name: "custom list", cfg: InformerConfig{ExcludeNamespaces: []string{"default"}}, wantAll: []string{"kube-system/coredns"}},
+		{name: "no default for a single namespace", cfg: InformerConfig{Namespace: "kube-system"}, wantAll: []string{"kube-system/coredns"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := []runtime.Object{testDeployment("default", "web", nil), testDeployment("kube-system", "coredns", nil)}
+			tt.cfg.Clientset = fake.NewClientset(objects...)
+			di, err := NewDeploymentInformer(tt.cfg)
+			require.NoError(t, err)
+
+			added := make(chan string, len(objects))
+			_, err = di.AddEventHandler(cache.ResourceEventHandlerFuncs{
+				AddFunc: func(obj interface{}) {
+					dep := obj.(*appsv1.Deployment)
+					added <- dep.Namespace + "/" + dep.Name
+				},
+			})
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			di.Start(ctx)
+			defer di.Stop()
+			require.True(t, di.WaitForCacheSync(ctx))
+			require.Eventually(t, func() bool { return len(added) == len(tt.wantAll) }, 5*time.Second, 10*time.Millisecond)
+
+			var got []string
+			for len(added) > 0 {
+				got = append(got, <-added)
+			}
+			require.ElementsMatch(t, tt.wantAll, got)
+		})
+	}
+}
+
+func TestDeploymentInformer_ExcludedEventsKeepInformerAlive(t *testing.T) {
+	clientset := fake.NewClientset(testDeployment("kube-system", "coredns", nil))
+	di, err := NewDeploymentInformer(InformerConfig{Clientset: clientset, StaleThreshold: time.Minute})
+	require.NoError(t, err)
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	di.clock = fakeClock
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	di.Start(ctx)
+	defer di.Stop()
+	require.True(t, di.WaitForCacheSync(ctx))
+
+	fakeClock.Step(time.Hour)
+	require.True(t, di.Degraded())
+	updated := testDeployment("kube-system", "coredns", map[string]string{"k8s-app": "kube-dns"})
+	updated.ResourceVersion = "2"
+	_, err = clientset.AppsV1().Deployments("kube-system").Update(ctx, updated, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		stats := di.Stats()
+		return stats.Adds == 1 && stats.Updates == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.False(t, di.Degraded(), "events from excluded namespaces still count as activity")
+}
+
+func TestDeploymentInformer_ExcludedDeleteTombstone(t *testing.T) {
+	di, err := NewDeploymentInformer(InformerConfig{Clientset: fake.NewClientset()})
+	require.NoError(t, err)
+	tombstone := cache.DeletedFinalStateUnknown{Key: "kube-system/coredns", Obj: testDeployment("kube-system", "coredns", nil)}
+	require.False(t, di.included(tombstone))
+	require.True(t, di.included(testDeployment("default", "web", nil)))
+}
@@ -0,0 +1,74 @@
+package informer
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// deploymentImageChanges is exposed on the controller-runtime metrics endpoint.
+var deploymentImageChanges = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "k8s_controller_deployment_image_changes_total",
+	Help: "Number of container image changes observed in deployment pod templates.",
+}, []string{"namespace", "deployment", "container"})
+
+func init() {
+	metrics.Registry.MustRegister(deploymentImageChanges)
+}
+
+// imageChange describes a container whose image differs between two revisions of a deployment.
+type imageChange struct {
+	Container string
+	OldImage  string
+	NewImage  string
+}
+
+// imageChanges compares the pod template images of old and new by container name.
+// Containers that were added or removed are not reported.
+func imageChanges(oldDep, newDep *appsv1.Deployment) []imageChange {
+	oldImages := templateImages(oldDep)
+	var changes []imageChange
+	for _, c := range templateContainers(newDep) {
+		if oldImage, ok := oldImages[c.Name]; ok && oldImage != c.Image {
+			changes = append(changes, imageChange{Container: c.Name, OldImage: oldImage, NewImage: c.Image})
+		}
+	}
+	return changes
+}
+
+func templateContainers(dep *appsv1.Deployment) []corev1.Container {
+	spec := dep.Spec.Template.Spec
+	return append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+}
+
+func templateImages(dep *appsv1.Deployment) map[string]string {
+	images := map[string]string{}
+	for _, c := range templateContainers(dep) {
+		images[c.Name] = c.Image
+	}
+	return images
+}
+
+// logImageChanges logs and counts every container image change between two deployment revisions.
+func logImageChanges(oldObj, newObj interface{}) {
+	oldDep, ok := oldObj.(*appsv1.Deployment)
+	if !ok {
+		return
+	}
+	newDep, ok := newObj.(*appsv1.Deployment)
+	if !ok {
+		return
+	}
+	for _, change := range imageChanges(oldDep, newDep) {
+		deploymentImageChanges.WithLabelValues(newDep.Namespace, newDep.Name, change.Container).Inc()
+		log.Info().
+			Str("namespace", newDep.Namespace).
+			Str("name", newDep.Name).
+			Str("container", change.Container).
+			Str("old_image", change.OldImage).
+			Str("new_image", change.NewImage).
+			Msg("Deployment image changed")
+	}
+}
@@ -0,0 +1,89 @@
+package informer
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// syncBuffer is a log sink safe for the informer's handler goroutine.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestDeploymentInformer_LogsImageChanges(t *testing.T) {
+	var logs syncBuffer
+	originalLogger := log.Logger
+	log.Logger = zerolog.New(&logs)
+	t.Cleanup(func() { log.Logger = originalLogger })
+
+	replicas := int32(1)
+	dep := testDeployment("default", "image-web", nil)
+	dep.Spec.Replicas = &replicas
+	dep.Spec.Template.Spec.Containers = []corev1.Container{{Name: "app", Image: "nginx:1.25"}}
+
+	clientset := fake.NewClientset(dep)
+	di, err := NewDeploymentInformer(InformerConfig{Clientset: clientset})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+		di.Stop()
+	})
+	di.Start(ctx)
+	require.True(t, di.WaitForCacheSync(ctx))
+
+	updates := 0
+	updateAndWait := func(mutate func(*appsv1.Deployment)) {
+		t.Helper()
+		updates++
+		current, err := clientset.AppsV1().Deployments("default").Get(ctx, "image-web", metav1.GetOptions{})
+		require.NoError(t, err)
+		mutate(current)
+		_, err = clientset.AppsV1().Deployments("default").Update(ctx, current, metav1.UpdateOptions{})
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			return strings.Count(logs.String(), "Deployment updated: image-web") >= updates
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	changes := deploymentImageChanges.WithLabelValues("default", "image-web", "app")
+	before := testutil.ToFloat64(changes)
+
+	updateAndWait(func(d *appsv1.Deployment) { replicas := int32(3); d.Spec.Replicas = &replicas })
+	require.NotContains(t, logs.String(), "Deployment image changed")
+	require.Equal(t, before, testutil.ToFloat64(changes))
+
+	updateAndWait(func(d *appsv1.Deployment) { d.Spec.Template.Spec.Containers[0].Image = "nginx:1.27" })
+	require.Eventually(t, func() bool {
+		return strings.Contains(logs.String(), "Deployment image changed")
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Contains(t, logs.String(), `"old_image":"nginx:1.25"`)
+	require.Contains(t, logs.String(), `"new_image":"nginx:1.27"`)
+	require.Equal(t, before+1, testutil.ToFloat64(changes))
+}
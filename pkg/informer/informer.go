@@ -2,7 +2,10 @@ package informer
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"sort"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -18,57 +21,102 @@ import (
 var (
 	deploymentInformer cache.SharedIndexInformer
 	podInformer        cache.SharedIndexInformer
+	deploymentsSynced  atomic.Bool
+
+	activeDeploymentInformer atomic.Pointer[DeploymentInformer]
 )
 
-// StartDeploymentInformer starts a shared informer for Deployments in the default namespace.
-func StartDeploymentInformer(ctx context.Context, clientset *kubernetes.Clientset) {
-	factory := informers.NewSharedInformerFactoryWithOptions(
-		clientset,
-		30*time.Second,
-		informers.WithNamespace("default"),
-		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
-			options.FieldSelector = fields.Everything().String()
-		}),
-	)
-	deploymentInformer = factory.Apps().V1().Deployments().Informer()
+// StartDeploymentInformer starts a shared informer for Deployments in the given namespace.
+// An empty namespace watches all namespaces.
+func StartDeploymentInformer(ctx context.Context, clientset *kubernetes.Clientset, namespace string) {
+	StartDeploymentInformerWithConfig(ctx, InformerConfig{Clientset: clientset, Namespace: namespace})
+}
 
-	deploymentInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: func(obj interface{}) {
-			log.Info().Msgf("Deployment added: %s", getDeploymentName(obj))
-		},
-		UpdateFunc: func(oldObj, newObj interface{}) {
-			log.Info().Msgf("Deployment updated: %s", getDeploymentName(newObj))
-		},
-		DeleteFunc: func(obj interface{}) {
-			log.Info().Msgf("Deployment deleted: %s", getDeploymentName(obj))
-		},
+// StartDeploymentInformerWithConfig is StartDeploymentInformer with full control over the informer settings.
+func StartDeploymentInformerWithConfig(ctx context.Context, cfg InformerConfig) {
+	if err := RunDeploymentInformer(ctx, cfg, 0, nil); err != nil {
+		log.Error().Err(err).Msg("Failed to start deployment informer")
+		os.Exit(1)
+	}
+}
+
+// RunDeploymentInformer starts the deployment informer described by cfg and blocks until
+// ctx is done. Unlike StartDeploymentInformerWithConfig it returns an error instead of
+// exiting when the informer cannot be created or, with a non-zero startTimeout, has not
+// synced within startTimeout. The timeout error carries the last list or watch failure.
+// With a nil onStartTimeout it is returned straight away, leaving the informer retrying in
+// the background until ctx is done. Otherwise onStartTimeout receives it and
+// RunDeploymentInformer keeps blocking until the informer has stopped, so DeploymentsSynced
+// turns true once the API server becomes reachable and the stop summary is always logged.
+func RunDeploymentInformer(ctx context.Context, cfg InformerConfig, startTimeout time.Duration, onStartTimeout func(error)) error {
+	di, err := NewDeploymentInformer(cfg)
+	if err != nil {
+		return err
+	}
+	var lastErr atomic.Pointer[error]
+	_ = di.informer.SetWatchErrorHandlerWithContext(func(ctx context.Context, r *cache.Reflector, err error) {
+		lastErr.Store(&err)
+		cache.DefaultWatchErrorHandler(ctx, r, err)
 	})
+	deploymentInformer = di.informer
+	activeDeploymentInformer.Store(di)
 
 	log.Info().Msg("Starting deployment informer...")
-	factory.Start(ctx.Done())
-	for t, ok := range factory.WaitForCacheSync(ctx.Done()) {
-		if !ok {
-			log.Error().Msgf("Failed to sync informer for %v", t)
-			os.Exit(1)
+	di.Start(ctx)
+	synced := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		defer di.Stop()
+		if di.WaitForCacheSync(ctx) {
+			deploymentsSynced.Store(true)
+			log.Info().Msg("Deployment informer cache synced. Watching for events...")
+			close(synced)
 		}
+		<-ctx.Done()
+	}()
+
+	var timeout <-chan time.Time
+	if startTimeout > 0 {
+		timer := time.NewTimer(startTimeout)
+		defer timer.Stop()
+		timeout = timer.C
 	}
-	log.Info().Msg("Deployment informer cache synced. Watching for events...")
-	<-ctx.Done() // Block until context is cancelled
+	select {
+	case <-synced:
+	case <-ctx.Done():
+	case <-timeout:
+		if ctx.Err() != nil {
+			break
+		}
+		err := fmt.Errorf("deployment informer cache did not sync within %s", startTimeout)
+		if last := lastErr.Load(); last != nil {
+			err = fmt.Errorf("%w: %w", err, *last)
+		}
+		if onStartTimeout == nil {
+			return err
+		}
+		onStartTimeout(err)
+	}
+	<-stopped
+	return nil
 }
 
-// StartPodInformer starts a shared informer for Pods in the default namespace.
-func StartPodInformer(ctx context.Context, clientset *kubernetes.Clientset) {
+// StartPodInformer starts a shared informer for Pods in the given namespace.
+// An empty namespace watches all namespaces.
+func StartPodInformer(ctx context.Context, clientset *kubernetes.Clientset, namespace string) {
 	factory := informers.NewSharedInformerFactoryWithOptions(
 		clientset,
 		30*time.Second,
-		informers.WithNamespace("default"),
+		informers.WithNamespace(namespace),
 		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
 			options.FieldSelector = fields.Everything().String()
 		}),
 	)
 	podInformer = factory.Core().V1().Pods().Informer()
 
-	podInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+	// Like DeploymentInformer.AddEventHandler, a panic is logged and the next event handled.
+	podInformer.AddEventHandler(recoveringHandler{resource: "pod", handler: cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			if pod, ok := obj.(*corev1.Pod); ok {
 				log.Info().Str("pod", pod.Name).Str("namespace", pod.Namespace).Str("phase", string(pod.Status.Phase)).Msg("Pod added")
@@ -91,7 +139,7 @@ func StartPodInformer(ctx context.Context, clientset *kubernetes.Clientset) {
 				log.Info().Str("pod", pod.Name).Str("namespace", pod.Namespace).Msg("Pod deleted")
 			}
 		},
-	})
+	}})
 
 	log.Info().Msg("Starting pod informer...")
 	factory.Start(ctx.Done())
@@ -106,18 +154,33 @@ func StartPodInformer(ctx context.Context, clientset *kubernetes.Clientset) {
 }
 
 // StartBothInformers starts both deployment and pod informers concurrently.
-func StartBothInformers(ctx context.Context, clientset *kubernetes.Clientset) {
+func StartBothInformers(ctx context.Context, clientset *kubernetes.Clientset, namespace string) {
 	// Start deployment informer in a goroutine
-	go StartDeploymentInformer(ctx, clientset)
+	go StartDeploymentInformer(ctx, clientset, namespace)
 
 	// Start pod informer in a goroutine
-	go StartPodInformer(ctx, clientset)
+	go StartPodInformer(ctx, clientset, namespace)
 
 	// Wait for context cancellation
 	<-ctx.Done()
 }
 
-// GetDeploymentNames returns a slice of deployment names from the informer's cache.
+// DeploymentsSynced reports whether the deployment informer started by StartDeploymentInformer
+// has completed its initial list, so GetDeploymentNames returns complete data.
+func DeploymentsSynced() bool {
+	return deploymentsSynced.Load()
+}
+
+// DeploymentsDegraded reports whether the informer started by StartDeploymentInformer
+// has gone stale, see DeploymentInformer.Degraded.
+func DeploymentsDegraded() bool {
+	di := activeDeploymentInformer.Load()
+	return di != nil && di.Degraded()
+}
+
+// GetDeploymentNames returns the names of the deployments in the informer's cache, sorted
+// alphabetically. The list is empty before the informer is started and incomplete until
+// its cache has synced, see DeploymentsSynced.
 func GetDeploymentNames() []string {
 	var names []string
 	if deploymentInformer == nil {
@@ -128,10 +191,12 @@ func GetDeploymentNames() []string {
 			names = append(names, d.Name)
 		}
 	}
+	sort.Strings(names)
 	return names
 }
 
-// GetPodNames returns a slice of pod names from the informer's cache.
+// GetPodNames returns the names of the pods in the informer's cache, sorted alphabetically.
+// Like GetDeploymentNames it is empty or incomplete until the pod informer has synced.
 func GetPodNames() []string {
 	var names []string
 	if podInformer == nil {
@@ -142,6 +207,7 @@ func GetPodNames() []string {
 			names = append(names, p.Name)
 		}
 	}
+	sort.Strings(names)
 	return names
 }
 
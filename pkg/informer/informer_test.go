@@ -6,9 +6,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/cache"
 
 	testutil "github.com/yourusername/k8s-controller-tutorial/pkg/testutil"
@@ -92,10 +96,56 @@ func TestStartDeploymentInformer_CoversFunction(t *testing.T) {
 
 	// Run StartDeploymentInformer in a goroutine
 	go func() {
-		StartDeploymentInformer(ctx, clientset)
+		StartDeploymentInformer(ctx, clientset, "default")
 	}()
 
 	// Give the informer some time to start and process events
 	time.Sleep(1 * time.Second)
 	cancel()
 }
+
+func TestGetNames_SortedAndStable(t *testing.T) {
+	originalDeployments, originalPods := deploymentInformer, podInformer
+	t.Cleanup(func() { deploymentInformer, podInformer = originalDeployments, originalPods })
+
+	deploymentInformer, podInformer = nil, nil
+	require.Empty(t, GetDeploymentNames(), "no informer started yet")
+	require.Empty(t, GetPodNames(), "no informer started yet")
+
+	// Fill the stores directly; the informers are never started.
+	factory := informers.NewSharedInformerFactory(fake.NewClientset(), 0)
+	deploymentInformer = factory.Apps().V1().Deployments().Informer()
+	podInformer = factory.Core().V1().Pods().Informer()
+	names := []string{"web", "api", "worker", "cache", "billing", "auth", "zeta", "gateway"}
+	for _, name := range names {
+		require.NoError(t, deploymentInformer.GetStore().Add(testDeployment("default", name, nil)))
+		require.NoError(t, podInformer.GetStore().Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name + "-0", Namespace: "default"}}))
+	}
+
+	want := []string{"api", "auth", "billing", "cache", "gateway", "web", "worker", "zeta"}
+	wantPods := make([]string, len(want))
+	for i, name := range want {
+		wantPods[i] = name + "-0"
+	}
+	for range 20 {
+		require.Equal(t, want, GetDeploymentNames())
+		require.Equal(t, wantPods, GetPodNames())
+	}
+}
+
+func TestRecoveringHandler_NamesResource(t *testing.T) {
+	var logs syncBuffer
+	originalLogger := log.Logger
+	log.Logger = zerolog.New(&logs)
+	t.Cleanup(func() { log.Logger = originalLogger })
+
+	h := recoveringHandler{resource: "pod", handler: cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) { panic("bad pod") },
+	}}
+	require.NotPanics(t, func() {
+		h.OnAdd(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-1"}}, false)
+	})
+	out := logs.String()
+	require.Contains(t, out, `"pod":"web-1"`)
+	require.Contains(t, out, "Recovered from panic in pod event handler")
+}
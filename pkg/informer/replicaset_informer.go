@@ -0,0 +1,162 @@
+package informer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// activeReplicaSetInformer is the informer started by RunReplicaSetInformer, read by
+// ListReplicaSets from other goroutines.
+var activeReplicaSetInformer atomic.Pointer[ReplicaSetInformer]
+
+// ReplicaSetInformer watches ReplicaSets and logs their events with the deployment that
+// owns them, which links rollouts to the pods they create.
+type ReplicaSetInformer struct {
+	factory  informers.SharedInformerFactory
+	informer cache.SharedIndexInformer
+}
+
+// NewReplicaSetInformer returns an informer for ReplicaSets in namespace that is not yet
+// started. An empty namespace watches all namespaces.
+func NewReplicaSetInformer(clientset kubernetes.Interface, namespace string) (*ReplicaSetInformer, error) {
+	factory := informers.NewSharedInformerFactoryWithOptions(
+		clientset,
+		30*time.Second,
+		informers.WithNamespace(namespace),
+	)
+	r := &ReplicaSetInformer{
+		factory:  factory,
+		informer: factory.Apps().V1().ReplicaSets().Informer(),
+	}
+	_, err := r.informer.AddEventHandler(recoveringHandler{resource: "replicaset", handler: cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if rs, ok := obj.(*appsv1.ReplicaSet); ok {
+				replicaSetEvent(log.Info(), rs).Msg("ReplicaSet added")
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if isResync(oldObj, newObj) {
+				return
+			}
+			oldRS, okOld := oldObj.(*appsv1.ReplicaSet)
+			newRS, okNew := newObj.(*appsv1.ReplicaSet)
+			if okOld && okNew {
+				replicaSetEvent(log.Info(), newRS).
+					Int32("old_ready", oldRS.Status.ReadyReplicas).
+					Msg("ReplicaSet updated")
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if rs, ok := obj.(*appsv1.ReplicaSet); ok {
+				replicaSetEvent(log.Info(), rs).Msg("ReplicaSet deleted")
+			}
+		},
+	}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add event handler: %w", err)
+	}
+	return r, nil
+}
+
+// Start runs the informer in the background until ctx is done or Stop is called.
+func (r *ReplicaSetInformer) Start(ctx context.Context) {
+	r.factory.Start(ctx.Done())
+}
+
+// WaitForCacheSync blocks until the initial list has been cached or ctx is done.
+func (r *ReplicaSetInformer) WaitForCacheSync(ctx context.Context) bool {
+	return cache.WaitForCacheSync(ctx.Done(), r.informer.HasSynced)
+}
+
+// Stop waits for the informer's goroutines to exit once the context passed to Start is done.
+func (r *ReplicaSetInformer) Stop() {
+	r.factory.Shutdown()
+}
+
+// List returns the cached ReplicaSets sorted by namespace and name.
+func (r *ReplicaSetInformer) List() []*appsv1.ReplicaSet {
+	var replicaSets []*appsv1.ReplicaSet
+	for _, obj := range r.informer.GetStore().List() {
+		if rs, ok := obj.(*appsv1.ReplicaSet); ok {
+			replicaSets = append(replicaSets, rs)
+		}
+	}
+	sort.Slice(replicaSets, func(i, j int) bool {
+		if replicaSets[i].Namespace != replicaSets[j].Namespace {
+			return replicaSets[i].Namespace < replicaSets[j].Namespace
+		}
+		return replicaSets[i].Name < replicaSets[j].Name
+	})
+	return replicaSets
+}
+
+// RunReplicaSetInformer starts a ReplicaSet informer for namespace, all namespaces if empty,
+// and blocks until ctx is done. It returns an error when the cache cannot sync; ctx being
+// done first is a clean stop and returns nil.
+func RunReplicaSetInformer(ctx context.Context, clientset kubernetes.Interface, namespace string) error {
+	r, err := NewReplicaSetInformer(clientset, namespace)
+	if err != nil {
+		return err
+	}
+	activeReplicaSetInformer.Store(r)
+
+	log.Info().Msg("Starting replicaset informer...")
+	r.Start(ctx)
+	defer r.Stop()
+	if !r.WaitForCacheSync(ctx) {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return errors.New("replicaset informer cache did not sync")
+	}
+	log.Info().Msg("ReplicaSet informer cache synced. Watching for events...")
+	<-ctx.Done() // Block until context is cancelled
+	return nil
+}
+
+// ListReplicaSets returns the ReplicaSets cached by the informer started by
+// RunReplicaSetInformer, sorted by namespace and name. It is empty before the informer is
+// started.
+func ListReplicaSets() []*appsv1.ReplicaSet {
+	r := activeReplicaSetInformer.Load()
+	if r == nil {
+		return nil
+	}
+	return r.List()
+}
+
+func replicaSetEvent(e *zerolog.Event, rs *appsv1.ReplicaSet) *zerolog.Event {
+	replicas := int32(1)
+	if rs.Spec.Replicas != nil {
+		replicas = *rs.Spec.Replicas
+	}
+	return e.Str("replicaset", rs.Name).
+		Str("namespace", rs.Namespace).
+		Str("deployment", owningDeployment(rs)).
+		Int32("replicas", replicas).
+		Int32("ready", rs.Status.ReadyReplicas)
+}
+
+// owningDeployment returns the name of the Deployment controlling obj, or "" for
+// ReplicaSets created on their own.
+func owningDeployment(obj metav1.Object) string {
+	if ref := metav1.GetControllerOf(obj); ref != nil && ref.Kind == "Deployment" {
+		return ref.Name
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package informer
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testReplicaSet(namespace, name, deployment string) *appsv1.ReplicaSet {
+	rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	if deployment != "" {
+		controller := true
+		rs.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       deployment,
+			Controller: &controller,
+		}}
+	}
+	return rs
+}
+
+func TestRunReplicaSetInformer(t *testing.T) {
+	var logs syncBuffer
+	originalLogger := log.Logger
+	log.Logger = zerolog.New(&logs)
+	t.Cleanup(func() { log.Logger = originalLogger })
+
+	clientset := fake.NewClientset(testReplicaSet("default", "web-5d8f", "web"))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- RunReplicaSetInformer(ctx, clientset, "default")
+	}()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done, "cancelling the context is a clean stop")
+		activeReplicaSetInformer.Store(nil)
+	})
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(logs.String(), "ReplicaSet informer cache synced")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, err := clientset.AppsV1().ReplicaSets("default").Create(ctx, testReplicaSet("default", "standalone", ""), metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, clientset.AppsV1().ReplicaSets("default").Delete(ctx, "web-5d8f", metav1.DeleteOptions{}))
+
+	require.Eventually(t, func() bool {
+		out := logs.String()
+		return strings.Contains(out, `"replicaset":"standalone"`) && strings.Contains(out, "ReplicaSet deleted")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	out := logs.String()
+	require.Contains(t, out, `"replicaset":"web-5d8f","namespace":"default","deployment":"web"`)
+	require.Contains(t, out, `"replicaset":"standalone","namespace":"default","deployment":""`)
+
+	names := make([]string, 0)
+	for _, rs := range ListReplicaSets() {
+		names = append(names, rs.Name)
+	}
+	require.Equal(t, []string{"standalone"}, names)
+}
+
+func TestListReplicaSets_NotStarted(t *testing.T) {
+	require.Empty(t, ListReplicaSets())
+}
+
+func TestOwningDeployment(t *testing.T) {
+	require.Equal(t, "web", owningDeployment(testReplicaSet("default", "web-5d8f", "web")))
+	require.Equal(t, "", owningDeployment(testReplicaSet("default", "standalone", "")))
+
+	rs := testReplicaSet("default", "job-abc", "web")
+	rs.OwnerReferences[0].Kind = "CronJob"
+	require.Equal(t, "", owningDeployment(rs))
+}
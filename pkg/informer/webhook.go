@@ -0,0 +1,182 @@
+package informer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// webhookQueueSize bounds the events waiting to be posted; further events are dropped.
+	webhookQueueSize = 100
+	webhookTimeout   = 5 * time.Second
+	webhookAttempts  = 3
+)
+
+// Webhook event types.
+const (
+	WebhookEventAdded   = "ADDED"
+	WebhookEventUpdated = "UPDATED"
+	WebhookEventDeleted = "DELETED"
+)
+
+// WebhookEvent is the JSON payload posted to the webhook URL for each deployment event.
+type WebhookEvent struct {
+	Type       string            `json:"type"`
+	Time       time.Time         `json:"time"`
+	Deployment DeploymentSummary `json:"deployment"`
+}
+
+// DeploymentSummary describes the deployment an event refers to.
+type DeploymentSummary struct {
+	Namespace         string   `json:"namespace"`
+	Name              string   `json:"name"`
+	Replicas          int32    `json:"replicas"`
+	ReadyReplicas     int32    `json:"readyReplicas"`
+	UpdatedReplicas   int32    `json:"updatedReplicas"`
+	AvailableReplicas int32    `json:"availableReplicas"`
+	Images            []string `json:"images,omitempty"`
+}
+
+// webhookNotifier posts deployment events to a URL from a background goroutine, so a slow
+// or unreachable endpoint never blocks the informer. Events that cannot be queued or
+// delivered are logged and dropped.
+type webhookNotifier struct {
+	url        string
+	client     *http.Client
+	events     chan WebhookEvent
+	retryDelay time.Duration
+}
+
+func newWebhookNotifier(url string) *webhookNotifier {
+	return &webhookNotifier{
+		url:        url,
+		client:     &http.Client{Timeout: webhookTimeout},
+		events:     make(chan WebhookEvent, webhookQueueSize),
+		retryDelay: time.Second,
+	}
+}
+
+// handler returns the event handler that queues webhook events. Resyncs, which carry
+// an unchanged resource version, are not sent.
+func (n *webhookNotifier) handler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			n.enqueue(WebhookEventAdded, obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if isResync(oldObj, newObj) {
+				return
+			}
+			n.enqueue(WebhookEventUpdated, newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			n.enqueue(WebhookEventDeleted, obj)
+		},
+	}
+}
+
+func (n *webhookNotifier) enqueue(eventType string, obj interface{}) {
+	dep, ok := obj.(*appsv1.Deployment)
+	if !ok {
+		return
+	}
+	event := WebhookEvent{Type: eventType, Time: time.Now().UTC(), Deployment: summarizeDeployment(dep)}
+	select {
+	case n.events <- event:
+	default:
+		log.Warn().Str("type", eventType).Str("deployment", dep.Name).Msg("Webhook queue full, dropping event")
+	}
+}
+
+// run delivers queued events until ctx is done.
+func (n *webhookNotifier) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-n.events:
+			if err := n.send(ctx, event); err != nil {
+				log.Error().Err(err).
+					Str("type", event.Type).
+					Str("namespace", event.Deployment.Namespace).
+					Str("deployment", event.Deployment.Name).
+					Msg("Dropping webhook event")
+			}
+		}
+	}
+}
+
+// send posts event, retrying network errors, 429 and 5xx responses.
+func (n *webhookNotifier) send(ctx context.Context, event WebhookEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= webhookAttempts; attempt++ {
+		retry, err := n.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry || attempt == webhookAttempts {
+			break
+		}
+		log.Debug().Err(err).Int("attempt", attempt).Msg("Webhook delivery failed, retrying")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(n.retryDelay * time.Duration(attempt)):
+		}
+	}
+	return fmt.Errorf("webhook delivery failed after %d attempt(s): %w", webhookAttempts, lastErr)
+}
+
+// post makes one delivery attempt and reports whether a failure is worth retrying.
+func (n *webhookNotifier) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook returned %s", resp.Status)
+}
+
+func summarizeDeployment(d *appsv1.Deployment) DeploymentSummary {
+	summary := DeploymentSummary{
+		Namespace:         d.Namespace,
+		Name:              d.Name,
+		Replicas:          1,
+		ReadyReplicas:     d.Status.ReadyReplicas,
+		UpdatedReplicas:   d.Status.UpdatedReplicas,
+		AvailableReplicas: d.Status.AvailableReplicas,
+	}
+	if d.Spec.Replicas != nil {
+		summary.Replicas = *d.Spec.Replicas
+	}
+	for _, c := range d.Spec.Template.Spec.Containers {
+		summary.Images = append(summary.Images, c.Image)
+	}
+	return summary
+}
@@ -0,0 +1,103 @@
+package informer
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// webhookRecorder is a test endpoint that collects the events posted to it.
+type webhookRecorder struct {
+	mu     sync.Mutex
+	events []WebhookEvent
+}
+
+func (r *webhookRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var event WebhookEvent
+	if err := json.NewDecoder(req.Body).Decode(&event); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.mu.Lock()
+	r.events = append(r.events, event)
+	r.mu.Unlock()
+}
+
+func (r *webhookRecorder) types() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var types []string
+	for _, e := range r.events {
+		types = append(types, e.Type)
+	}
+	return types
+}
+
+func TestDeploymentInformer_Webhook(t *testing.T) {
+	recorder := &webhookRecorder{}
+	srv := httptest.NewServer(recorder)
+	defer srv.Close()
+
+	di := startTestInformer(t, InformerConfig{WebhookURL: srv.URL}, testDeployment("default", "web", nil))
+
+	require.Eventually(t, func() bool { return len(recorder.types()) == 1 }, 5*time.Second, 10*time.Millisecond)
+	recorder.mu.Lock()
+	first := recorder.events[0]
+	recorder.mu.Unlock()
+	require.Equal(t, WebhookEventAdded, first.Type)
+	require.Equal(t, "default", first.Deployment.Namespace)
+	require.Equal(t, "web", first.Deployment.Name)
+	require.Equal(t, int32(1), first.Deployment.Replicas)
+
+	clientset := di.config.Clientset
+	require.NoError(t, clientset.AppsV1().Deployments("default").Delete(context.Background(), "web", metav1.DeleteOptions{}))
+	require.Eventually(t, func() bool {
+		types := recorder.types()
+		return len(types) == 2 && types[1] == WebhookEventDeleted
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestWebhookNotifier_Retries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < webhookAttempts {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	n := newWebhookNotifier(srv.URL)
+	n.retryDelay = time.Millisecond
+	require.NoError(t, n.send(context.Background(), WebhookEvent{Type: WebhookEventAdded}))
+	require.Equal(t, int32(webhookAttempts), calls.Load())
+}
+
+func TestWebhookNotifier_ClientErrorNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	n := newWebhookNotifier(srv.URL)
+	n.retryDelay = time.Millisecond
+	require.Error(t, n.send(context.Background(), WebhookEvent{Type: WebhookEventAdded}))
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestWebhookNotifier_DropsWhenQueueFull(t *testing.T) {
+	n := newWebhookNotifier("http://127.0.0.1:0")
+	for i := 0; i < webhookQueueSize+10; i++ {
+		n.enqueue(WebhookEventUpdated, testDeployment("default", "web", nil))
+	}
+	require.Len(t, n.events, webhookQueueSize)
+}
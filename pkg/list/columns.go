@@ -0,0 +1,85 @@
+// Package list renders resource tables from a registry of columns, so downstream
+// code can add columns to the list commands without editing them.
+package list
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Column is one column of a resource table.
+type Column struct {
+	// Header is printed in the table header, conventionally in upper case.
+	Header string
+	// Value extracts the cell for obj, which is a typed object such as *appsv1.Deployment.
+	Value func(obj runtime.Object) string
+}
+
+var (
+	mu       sync.RWMutex
+	defaults = map[string][]Column{}
+	extra    = map[string][]Column{}
+)
+
+// SetDefaultColumns sets the built-in columns of resource, e.g. "deployments".
+func SetDefaultColumns(resource string, cols []Column) {
+	mu.Lock()
+	defer mu.Unlock()
+	defaults[resource] = append([]Column(nil), cols...)
+}
+
+// RegisterColumns adds cols after the built-in and previously registered columns of resource.
+func RegisterColumns(resource string, cols []Column) {
+	mu.Lock()
+	defer mu.Unlock()
+	extra[resource] = append(extra[resource], cols...)
+}
+
+// Columns returns the columns rendered for resource: the defaults followed by registered ones.
+func Columns(resource string) []Column {
+	mu.RLock()
+	defer mu.RUnlock()
+	cols := append([]Column(nil), defaults[resource]...)
+	return append(cols, extra[resource]...)
+}
+
+// PrintTable writes objs as a table of the columns registered for resource. With
+// withNamespace set, a leading NAMESPACE column is added.
+func PrintTable(w io.Writer, resource string, objs []runtime.Object, withNamespace bool) error {
+	cols := Columns(resource)
+	if len(cols) == 0 {
+		return fmt.Errorf("no columns registered for %q", resource)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	headers := make([]string, 0, len(cols)+1)
+	if withNamespace {
+		headers = append(headers, "NAMESPACE")
+	}
+	for _, col := range cols {
+		headers = append(headers, col.Header)
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+	for _, obj := range objs {
+		cells := make([]string, 0, len(headers))
+		if withNamespace {
+			accessor, err := meta.Accessor(obj)
+			if err != nil {
+				return err
+			}
+			cells = append(cells, accessor.GetNamespace())
+		}
+		for _, col := range cols {
+			cells = append(cells, col.Value(obj))
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
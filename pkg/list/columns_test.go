@@ -0,0 +1,68 @@
+package list
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func resetRegistry(t *testing.T) {
+	t.Helper()
+	mu.Lock()
+	defaults, extra = map[string][]Column{}, map[string][]Column{}
+	mu.Unlock()
+}
+
+func nameColumn() Column {
+	return Column{Header: "NAME", Value: func(obj runtime.Object) string { return obj.(*appsv1.Deployment).Name }}
+}
+
+func TestRegisterColumns_AddsCustomColumn(t *testing.T) {
+	resetRegistry(t)
+	SetDefaultColumns("deployments", []Column{nameColumn()})
+
+	// A downstream fork adds the owning team from a label.
+	RegisterColumns("deployments", []Column{{
+		Header: "TEAM",
+		Value: func(obj runtime.Object) string {
+			if team := obj.(*appsv1.Deployment).Labels["team"]; team != "" {
+				return team
+			}
+			return "<none>"
+		},
+	}})
+
+	objs := []runtime.Object{
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "prod", Labels: map[string]string{"team": "payments"}}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "prod"}},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, PrintTable(&buf, "deployments", objs, true))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	require.Equal(t, []string{"NAMESPACE", "NAME", "TEAM"}, strings.Fields(lines[0]))
+	require.Equal(t, []string{"prod", "api", "payments"}, strings.Fields(lines[1]))
+	require.Equal(t, []string{"prod", "web", "<none>"}, strings.Fields(lines[2]))
+}
+
+func TestColumns_DefaultsFirstRegardlessOfOrder(t *testing.T) {
+	resetRegistry(t)
+	RegisterColumns("deployments", []Column{{Header: "EXTRA", Value: func(runtime.Object) string { return "" }}})
+	SetDefaultColumns("deployments", []Column{nameColumn()})
+
+	cols := Columns("deployments")
+	require.Len(t, cols, 2)
+	require.Equal(t, "NAME", cols[0].Header)
+	require.Equal(t, "EXTRA", cols[1].Header)
+}
+
+func TestPrintTable_UnknownResource(t *testing.T) {
+	resetRegistry(t)
+	require.Error(t, PrintTable(&bytes.Buffer{}, "widgets", nil, false))
+}
@@ -9,10 +9,12 @@ import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 
 	"github.com/stretchr/testify/require"
+	"github.com/yourusername/k8s-controller-tutorial/pkg/deploystatus"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
@@ -143,4 +145,27 @@ func SetupEnv(t *testing.T) (*envtest.Environment, *kubernetes.Clientset, func()
 	return env, clientset, cleanup
 }
 
+// WaitForDeployment polls the deployment until cond is met, failing the test after timeout,
+// and returns the deployment as last read. Pass deploystatus.Available for the usual
+// available-replicas check.
+func WaitForDeployment(t *testing.T, clientset kubernetes.Interface, namespace, name string, cond deploystatus.Condition, timeout time.Duration) *appsv1.Deployment {
+	t.Helper()
+	var dep *appsv1.Deployment
+	err := wait.PollUntilContextTimeout(context.Background(), 100*time.Millisecond, timeout, true, func(ctx context.Context) (bool, error) {
+		current, err := clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		dep = current
+		return cond.Met(dep), nil
+	})
+	if err != nil {
+		if dep == nil {
+			t.Fatalf("deployment %s/%s not found within %s", namespace, name, timeout)
+		}
+		t.Fatalf("deployment %s/%s not %s within %s: %+v", namespace, name, cond, timeout, dep.Status)
+	}
+	return dep
+}
+
 func int32Ptr(i int32) *int32 { return &i }
\ No newline at end of file
@@ -1,6 +1,15 @@
 package testutil
 
-import "testing"
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yourusername/k8s-controller-tutorial/pkg/deploystatus"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
 
 func TestInt32Ptr(t *testing.T) {
 	v := int32(42)
@@ -9,3 +18,26 @@ func TestInt32Ptr(t *testing.T) {
 		t.Errorf("int32Ptr(%d) = %v, want pointer to %d", v, ptr, v)
 	}
 }
+
+func TestWaitForDeployment(t *testing.T) {
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Generation: 2},
+		Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+		Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+	}
+	clientset := fake.NewClientset(dep)
+
+	// Available is met straight away, complete only once the new generation is observed.
+	WaitForDeployment(t, clientset, "default", "web", deploystatus.Available, time.Second)
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		updated := dep.DeepCopy()
+		updated.Status.ObservedGeneration = 2
+		_, _ = clientset.AppsV1().Deployments("default").UpdateStatus(context.Background(), updated, metav1.UpdateOptions{})
+	}()
+	got := WaitForDeployment(t, clientset, "default", "web", deploystatus.Complete, 5*time.Second)
+	if got.Status.ObservedGeneration != 2 {
+		t.Errorf("returned deployment has observedGeneration %d, want 2", got.Status.ObservedGeneration)
+	}
+}
@@ -0,0 +1,46 @@
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// SplitKubeconfigServer is the API server of the context written by WriteSplitKubeconfig.
+	SplitKubeconfigServer = "https://staging.example.com:6443"
+	// SplitKubeconfigToken is the bearer token of the user written by WriteSplitKubeconfig.
+	SplitKubeconfigToken = "secret"
+)
+
+// WriteSplitKubeconfig writes a kubeconfig spread over two files in a temporary directory,
+// for testing KUBECONFIG-style merging. The first file only selects the "staging" context;
+// the context, its cluster and its user live in the second, so neither file works alone.
+func WriteSplitKubeconfig(t *testing.T) (first, second string) {
+	t.Helper()
+	dir := t.TempDir()
+	first, second = filepath.Join(dir, "a"), filepath.Join(dir, "b")
+	require.NoError(t, os.WriteFile(first, []byte(`apiVersion: v1
+kind: Config
+current-context: staging
+`), 0o600))
+	require.NoError(t, os.WriteFile(second, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: staging
+  cluster:
+    server: `+SplitKubeconfigServer+`
+users:
+- name: staging
+  user:
+    token: `+SplitKubeconfigToken+`
+contexts:
+- name: staging
+  context:
+    cluster: staging
+    user: staging
+`), 0o600))
+	return first, second
+}
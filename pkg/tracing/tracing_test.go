@@ -0,0 +1,29 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestSetup_DisabledWithoutEndpoint(t *testing.T) {
+	before := otel.GetTracerProvider()
+	shutdown, err := Setup(context.Background(), "", "k8s-controller", "test")
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+	require.Equal(t, before, otel.GetTracerProvider())
+}
+
+func TestSetup_InstallsProvider(t *testing.T) {
+	shutdown, err := Setup(context.Background(), "http://localhost:4317", "k8s-controller", "test")
+	require.NoError(t, err)
+	require.IsType(t, &sdktrace.TracerProvider{}, otel.GetTracerProvider())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_ = shutdown(ctx)
+}